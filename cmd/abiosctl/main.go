@@ -0,0 +1,181 @@
+// Command abiosctl queries live data from the terminal, through the wrapper's
+// HTTP API or, with -direct, from Abios itself:
+//
+//	abiosctl series live
+//	abiosctl teams get 12 -o json
+//	abiosctl players live -game cs2 -o csv
+//	ABIOS_TOKEN=... abiosctl -direct series live
+//	abiosctl -direct -config config.yaml teams live
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const usage = `Usage: abiosctl <resource> <command> [args] [flags]
+
+Commands:
+  series live          the live series
+  teams live           the teams playing in live series
+  teams get <id>...    teams by ID
+  players live         the players on the rosters of live series
+  players get <id>...  players by ID
+
+Flags may come before or after the command:
+`
+
+// command is a resource and what to do with it.
+type command struct {
+	resource string
+	verb     string
+}
+
+var commands = map[command]bool{
+	{"series", "live"}:  false,
+	{"teams", "live"}:   false,
+	{"teams", "get"}:    true,
+	{"players", "live"}: false,
+	{"players", "get"}:  true,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	fs := flag.NewFlagSet("abiosctl", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	server := fs.String("server", envOr("ABIOSCTL_SERVER", "http://localhost:8080"), "`URL` of the wrapper, also ABIOSCTL_SERVER")
+	direct := fs.Bool("direct", false, "query Abios with the client package instead of the wrapper, configured and authenticated as the server is")
+	configFile := fs.String("config", "", "YAML, TOML or JSON config `file` for -direct, as read by the server")
+	baseURL := fs.String("base-url", "", "Abios `URL` for -direct, overriding the config file and ABIOS_API_BASE_URL")
+	output := fs.String("output", "table", "output `format`: table, json or csv")
+	fs.StringVar(output, "o", "table", "`format`, shorthand for -output")
+	game := fs.String("game", "", "comma separated game `slugs` to limit live data to, e.g. cs2,dota2")
+	timeout := fs.Duration("timeout", 30*time.Second, "give up after this long")
+
+	positional, err := parseInterspersed(fs, args)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	if len(positional) < 2 {
+		fs.Usage()
+		return 2
+	}
+	cmd := command{resource: positional[0], verb: positional[1]}
+	takesIDs, ok := commands[cmd]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", cmd.resource+" "+cmd.verb)
+		fs.Usage()
+		return 2
+	}
+
+	ids, err := parseIDs(positional[2:])
+	switch {
+	case err != nil:
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	case takesIDs && len(ids) == 0:
+		fmt.Fprintf(os.Stderr, "%s %s needs at least one ID\n", cmd.resource, cmd.verb)
+		return 2
+	case !takesIDs && len(ids) > 0:
+		fmt.Fprintf(os.Stderr, "%s %s takes no arguments\n", cmd.resource, cmd.verb)
+		return 2
+	}
+
+	format, ok := formats[*output]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown output format %q, want table, json or csv\n", *output)
+		return 2
+	}
+
+	var src source
+	if *direct {
+		if src, err = newDirectSource(*configFile, *baseURL); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	} else {
+		src = newServerSource(*server)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var rows []row
+	if takesIDs {
+		rows, err = src.get(ctx, cmd.resource, ids)
+	} else {
+		rows, err = src.live(ctx, cmd.resource, splitComma(*game))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	sortByID(rows)
+	if err := format(os.Stdout, columns[cmd.resource], rows); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// parseInterspersed parses fs from args, allowing flags after the
+// positional arguments as in "players live -game cs2", and returns the
+// positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func parseIDs(args []string) ([]int, error) {
+	ids := make([]int, 0, len(args))
+	for _, a := range args {
+		id, err := strconv.Atoi(a)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("invalid ID %q: must be a positive integer", a)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func splitComma(s string) []string {
+	var parts []string
+	for p := range strings.SplitSeq(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// column is a table or CSV column and the dotted path of its value.
+type column struct {
+	header string
+	path   string
+}
+
+// columns are what is printed of each resource in table and CSV output.
+// JSON output prints items whole.
+var columns = map[string][]column{
+	"series":  {{"ID", "id"}, {"TITLE", "title"}, {"GAME", "game.slug"}},
+	"teams":   {{"ID", "id"}, {"NAME", "name"}},
+	"players": {{"ID", "id"}, {"NICKNAME", "nick_name"}},
+}
+
+// formats write rows in each -output format.
+var formats = map[string]func(w io.Writer, cols []column, rows []row) error{
+	"table": writeTable,
+	"json":  writeJSON,
+	"csv":   writeCSV,
+}
+
+func writeTable(w io.Writer, cols []column, rows []row) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	headers := make([]string, len(cols))
+	for i, c := range cols {
+		headers[i] = c.header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, r := range rows {
+		fmt.Fprintln(tw, strings.Join(values(cols, r), "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeJSON(w io.Writer, _ []column, rows []row) error {
+	if rows == nil {
+		rows = []row{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
+
+func writeCSV(w io.Writer, cols []column, rows []row) error {
+	cw := csv.NewWriter(w)
+
+	headers := make([]string, len(cols))
+	for i, c := range cols {
+		headers[i] = c.path
+	}
+	_ = cw.Write(headers)
+	for _, r := range rows {
+		_ = cw.Write(values(cols, r))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func values(cols []column, r row) []string {
+	out := make([]string, len(cols))
+	for i, c := range cols {
+		if v := lookup(r, c.path); v != nil {
+			out[i] = fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+// lookup follows a dotted path into r, nil when any part is missing.
+func lookup(r row, path string) any {
+	var v any = r
+	for key := range strings.SplitSeq(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// sortByID orders rows by ascending ID, whatever order the source answered
+// in.
+func sortByID(rows []row) {
+	slices.SortStableFunc(rows, func(a, b row) int {
+		return cmp.Compare(id(a), id(b))
+	})
+}
+
+func id(r row) int64 {
+	n, _ := r["id"].(json.Number)
+	i, _ := n.Int64()
+	return i
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var outputSeries = []row{
+	{"id": json.Number("2"), "title": "Semi, Final", "game": map[string]any{"slug": "cs2"}},
+	{"id": json.Number("10"), "title": "Grand Final"},
+}
+
+func TestWriteTable(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeTable(&out, columns["series"], outputSeries))
+
+	assert.Equal(t, ""+
+		"ID  TITLE        GAME\n"+
+		"2   Semi, Final  cs2\n"+
+		"10  Grand Final  \n",
+		out.String())
+}
+
+func TestWriteCSV(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeCSV(&out, columns["series"], outputSeries))
+
+	assert.Equal(t, ""+
+		"id,title,game.slug\n"+
+		"2,\"Semi, Final\",cs2\n"+
+		"10,Grand Final,\n",
+		out.String())
+}
+
+func TestWriteJSONWithoutRows(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeJSON(&out, nil, nil))
+	assert.Equal(t, "[]\n", out.String())
+}
+
+func TestSortByID(t *testing.T) {
+	rows := []row{{"id": json.Number("10")}, {"id": json.Number("2")}, {"id": json.Number("7")}}
+	sortByID(rows)
+
+	var ids []int64
+	for _, r := range rows {
+		ids = append(ids, id(r))
+	}
+	assert.Equal(t, []int64{2, 7, 10}, ids)
+}
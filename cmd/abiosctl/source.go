@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/benjaminmishra/abios-apis/internal/config"
+	"github.com/benjaminmishra/abios-apis/internal/service"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+)
+
+// row is one item as JSON decodes it, so both sources print alike.
+type row = map[string]any
+
+// source is where abiosctl gets its data. resource is series, teams or
+// players.
+type source interface {
+	live(ctx context.Context, resource string, games []string) ([]row, error)
+	get(ctx context.Context, resource string, ids []int) ([]row, error)
+}
+
+// serverSource queries the wrapper's HTTP API.
+type serverSource struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newServerSource(baseURL string) *serverSource {
+	return &serverSource{baseURL: strings.TrimRight(baseURL, "/"), client: http.DefaultClient}
+}
+
+func (s *serverSource) live(ctx context.Context, resource string, games []string) ([]row, error) {
+	query := url.Values{}
+	if len(games) > 0 {
+		query.Set("game", strings.Join(games, ","))
+	}
+	return s.list(ctx, resource, query)
+}
+
+// get filters the live items, as the wrapper serves nothing else; teams and
+// players that are not playing are not found.
+func (s *serverSource) get(ctx context.Context, resource string, ids []int) ([]row, error) {
+	set := make([]string, len(ids))
+	for i, id := range ids {
+		set[i] = strconv.Itoa(id)
+	}
+	return s.list(ctx, resource, url.Values{"filter": {"id={" + strings.Join(set, ",") + "}"}})
+}
+
+// list fetches every page of /<resource>/live.
+func (s *serverSource) list(ctx context.Context, resource string, query url.Values) ([]row, error) {
+	query.Set("limit", "200")
+
+	all := []row{}
+	for {
+		page, err := s.page(ctx, resource, query)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Data...)
+		if page.NextCursor == "" {
+			return all, nil
+		}
+		query.Set("cursor", page.NextCursor)
+	}
+}
+
+type listResponse struct {
+	Data       []row  `json:"data"`
+	NextCursor string `json:"next_cursor"`
+}
+
+func (s *serverSource) page(ctx context.Context, resource string, query url.Values) (*listResponse, error) {
+	u := s.baseURL + "/" + resource + "/live?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("server: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// nothing is live
+		return &listResponse{}, nil
+	default:
+		return nil, fmt.Errorf("server: HTTP %d: %s", resp.StatusCode, problemDetail(body))
+	}
+
+	var page listResponse
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&page); err != nil {
+		return nil, fmt.Errorf("server: decoding %s: %w", u, err)
+	}
+	return &page, nil
+}
+
+// problemDetail is the detail of a problem+json body, or the body itself.
+func problemDetail(body []byte) string {
+	var p struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	}
+	if json.Unmarshal(body, &p) == nil && (p.Detail != "" || p.Title != "") {
+		if p.Detail != "" {
+			return p.Detail
+		}
+		return p.Title
+	}
+	return strings.TrimSpace(string(body))
+}
+
+// directSource queries Abios with the client package, shaping live data as
+// the wrapper does.
+type directSource struct {
+	client      *abios.Client
+	liveService service.LiveService
+}
+
+// newDirectSource builds the client as the server does, from the
+// configuration it would load from configFile and the ABIOS_* variables.
+// A non-empty baseURL overrides the configured one.
+func newDirectSource(configFile, baseURL string) (*directSource, error) {
+	var args []string
+	if configFile != "" {
+		args = append(args, "-config", configFile)
+	}
+	if baseURL != "" {
+		args = append(args, "-base-url", baseURL)
+	}
+
+	cfg, err := config.Load(args)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := cfg.ClientOptions(cfg.TokenPool())
+	if err != nil {
+		return nil, err
+	}
+
+	client := abios.NewClient(opts...)
+	return &directSource{client: client, liveService: service.NewAbiosLiveService(client)}, nil
+}
+
+func (s *directSource) live(ctx context.Context, resource string, games []string) ([]row, error) {
+	switch resource {
+	case "series":
+		return rows(s.liveService.GetLiveSeries(ctx, games))
+	case "teams":
+		return rows(s.liveService.GetLiveTeams(ctx, games))
+	case "players":
+		return rows(s.liveService.GetLivePlayers(ctx, games))
+	}
+	return nil, fmt.Errorf("unknown resource %q", resource)
+}
+
+func (s *directSource) get(ctx context.Context, resource string, ids []int) ([]row, error) {
+	switch resource {
+	case "teams":
+		return rows(s.client.GetTeamsByID(ctx, ids))
+	case "players":
+		return rows(s.client.GetPlayersByID(ctx, ids))
+	}
+	return nil, fmt.Errorf("unknown resource %q", resource)
+}
+
+// rows converts items to rows through JSON, numbers kept as json.Number.
+func rows[T any](items []T, err error) ([]row, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	out := []row{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
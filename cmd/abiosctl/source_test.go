@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerSourceFollowsCursors(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data": [{"id": 1, "name": "Team A"}, {"id": 2, "name": "Team B"}], "next_cursor": "c1"}`,
+		"c1": `{"data": [{"id": 3, "name": "Team C"}], "next_cursor": "c2"}`,
+		"c2": `{"data": []}`,
+	}
+
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/teams/live", r.URL.Path)
+		assert.Equal(t, "200", r.URL.Query().Get("limit"))
+		assert.Equal(t, "cs2,dota2", r.URL.Query().Get("game"))
+
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(pages[cursor]))
+	}))
+	defer srv.Close()
+
+	rows, err := newServerSource(srv.URL+"/").live(context.Background(), "teams", []string{"cs2", "dota2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "c1", "c2"}, cursors)
+	assert.Equal(t, []row{
+		{"id": json.Number("1"), "name": "Team A"},
+		{"id": json.Number("2"), "name": "Team B"},
+		{"id": json.Number("3"), "name": "Team C"},
+	}, rows)
+}
+
+func TestServerSourceGetFiltersByID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/players/live", r.URL.Path)
+		assert.Equal(t, "id={4,9}", r.URL.Query().Get("filter"))
+		_, _ = w.Write([]byte(`{"data": [{"id": 4, "nick_name": "p4"}]}`))
+	}))
+	defer srv.Close()
+
+	rows, err := newServerSource(srv.URL).get(context.Background(), "players", []int{4, 9})
+	require.NoError(t, err)
+	assert.Equal(t, []row{{"id": json.Number("4"), "nick_name": "p4"}}, rows)
+}
+
+func TestServerSourceErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		expectedError string
+	}{
+		{
+			name:   "Nothing Live",
+			status: http.StatusNotFound,
+			body:   `{"title": "Not Found", "status": 404}`,
+		},
+		{
+			name:          "Problem",
+			status:        http.StatusBadRequest,
+			body:          `{"title": "Bad Request", "status": 400, "detail": "unknown game: \"chess\""}`,
+			expectedError: `server: HTTP 400: unknown game: "chess"`,
+		},
+		{
+			name:          "Plain Text",
+			status:        http.StatusBadGateway,
+			body:          "upstream down\n",
+			expectedError: "server: HTTP 502: upstream down",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			rows, err := newServerSource(srv.URL).live(context.Background(), "series", nil)
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+				assert.Empty(t, rows)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
+
+func TestDirectSourceUsesServerConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("abios:\n  token: from-file\n"), 0o600))
+
+	tests := []struct {
+		name            string
+		configFile      string
+		tokens          string
+		expectedSecrets []string
+	}{
+		{
+			name:            "Config File",
+			configFile:      configFile,
+			expectedSecrets: []string{"from-file"},
+		},
+		{
+			name:            "Token Pool",
+			tokens:          "pooled-1,pooled-2",
+			expectedSecrets: []string{"pooled-1", "pooled-2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var secrets []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				secrets = append(secrets, r.Header.Get("Abios-Secret"))
+				mu.Unlock()
+				_, _ = w.Write([]byte(`[{"id": 12, "name": "Team A"}]`))
+			}))
+			defer srv.Close()
+
+			t.Setenv("ABIOS_TOKEN", "")
+			t.Setenv("ABIOS_TOKENS", tt.tokens)
+			t.Setenv("ABIOS_API_BASE_URL", "http://127.0.0.1:1")
+
+			// -base-url wins over the environment
+			src, err := newDirectSource(tt.configFile, srv.URL)
+			require.NoError(t, err)
+
+			rows, err := src.get(context.Background(), "teams", []int{12})
+			require.NoError(t, err)
+			assert.Equal(t, []row{{"id": json.Number("12"), "name": "Team A"}}, rows)
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.Len(t, secrets, 1)
+			assert.Contains(t, tt.expectedSecrets, secrets[0])
+		})
+	}
+}
+
+func TestDirectSourceRejectsInvalidConfig(t *testing.T) {
+	t.Setenv("ABIOS_TOKEN", "")
+	t.Setenv("ABIOS_TOKENS", "")
+	t.Setenv("ABIOS_TOKEN_FILE", "")
+	t.Setenv("ABIOS_TOKEN_COMMAND", "")
+
+	_, err := newDirectSource("", "")
+	assert.ErrorContains(t, err, "abios.token: must be set")
+}
+
+func TestDirectSourceReplaysCassettes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 12, "name": "Team A"}]`))
+	}))
+	baseURL := srv.URL
+
+	t.Setenv("ABIOS_TOKENS", "")
+	t.Setenv("ABIOS_CASSETTE_DIR", t.TempDir())
+	t.Setenv("ABIOS_CASSETTE_MODE", "record")
+	t.Setenv("ABIOS_TOKEN", "token")
+
+	src, err := newDirectSource("", baseURL)
+	require.NoError(t, err)
+	_, err = src.get(context.Background(), "teams", []int{12})
+	require.NoError(t, err)
+	srv.Close()
+
+	// replaying needs neither a token nor Abios
+	t.Setenv("ABIOS_CASSETTE_MODE", "replay")
+	t.Setenv("ABIOS_TOKEN", "")
+
+	src, err = newDirectSource("", baseURL)
+	require.NoError(t, err)
+	rows, err := src.get(context.Background(), "teams", []int{12})
+	require.NoError(t, err)
+	assert.Equal(t, []row{{"id": json.Number("12"), "name": "Team A"}}, rows)
+}
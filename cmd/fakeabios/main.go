@@ -0,0 +1,84 @@
+// Command fakeabios serves a fake Abios Atlas API for local development:
+//
+//	go run ./cmd/fakeabios -token dev -error-rate 0.1 -rps 5
+//	go run ./cmd/fakeabios -scenario internal/fakeabios/scenarios/major_final.yaml -speed 60
+//	ABIOS_API_BASE_URL=http://localhost:8081 ABIOS_TOKEN=dev go run ./cmd/server
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/fakeabios"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "listen address")
+	fixtures := flag.String("fixtures", "", "directory of <resource>.json fixture files, the built-in dataset when empty")
+	scenario := flag.String("scenario", "", "YAML or JSON scenario file to play, instead of fixtures")
+	speed := flag.Float64("speed", 1, "how many times faster than the wall clock the scenario plays")
+	token := flag.String("token", "", "required Abios-Secret header value, unchecked when empty")
+	latency := flag.Duration("latency", 0, "delay added to every response")
+	jitter := flag.Duration("jitter", 0, "random extra delay of up to this much")
+	errorRate := flag.Float64("error-rate", 0, "fraction of requests failed with HTTP 500, 0 to 1")
+	rps := flag.Float64("rps", 0, "requests per second before answering 429, unlimited when 0")
+	burst := flag.Int("burst", 1, "rate limit burst")
+	retryAfter := flag.Duration("retry-after", time.Second, "Retry-After sent with 429 responses")
+	seed := flag.Uint64("seed", uint64(time.Now().UnixNano()), "seed for error injection and jitter")
+	flag.Parse()
+
+	var data fakeabios.Source = fakeabios.DefaultDataset()
+	switch {
+	case *scenario != "":
+		s, err := fakeabios.LoadScenario(*scenario)
+		if err != nil {
+			log.Fatalf("failed to load scenario: %v", err)
+		}
+		data = fakeabios.NewSimulator(s, &fakeabios.SystemClock{Speed: *speed})
+	case *fixtures != "":
+		ds, err := fakeabios.LoadDataset(os.DirFS(*fixtures))
+		if err != nil {
+			log.Fatalf("failed to load fixtures: %v", err)
+		}
+		data = ds
+	}
+
+	srv := &http.Server{
+		Addr: *addr,
+		Handler: fakeabios.NewServer(data, fakeabios.Options{
+			Token:          *token,
+			Latency:        *latency,
+			Jitter:         *jitter,
+			ErrorRate:      *errorRate,
+			RequestsPerSec: *rps,
+			Burst:          *burst,
+			RetryAfter:     *retryAfter,
+			Seed:           *seed,
+		}),
+	}
+
+	go func() {
+		log.Printf("fake abios listening on %s", *addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("server error: %s", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Printf("shutdown error: %s", err)
+	}
+}
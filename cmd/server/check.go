@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/config"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+)
+
+// runCheckUpstream makes one authenticated call to Abios with every
+// configured credential and reports the latency, whether the credential was
+// accepted and the rate limit headers. It fails unless every call succeeds,
+// for use in deploy pipelines. Cassettes are ignored; this is about the real
+// upstream.
+func runCheckUpstream(args []string) int {
+	fs := newFlagSet("check-upstream")
+	flags := config.BindFlags(fs)
+	_ = fs.Parse(args)
+
+	cfg, err := flags.Load()
+	if err != nil {
+		printConfigError(err)
+		return 1
+	}
+
+	type credential struct {
+		name   string
+		source abios.TokenSource
+	}
+	credentials := []credential{{name: "token", source: cfg.TokenSource()}}
+	if len(cfg.Tokens) > 0 {
+		// named as in the token pool metrics
+		credentials = credentials[:0]
+		for i, t := range cfg.Tokens {
+			credentials = append(credentials, credential{name: fmt.Sprintf("token-%d", i+1), source: t})
+		}
+	}
+
+	fmt.Printf("upstream %s\n", cfg.ApiBaseUrl)
+
+	ok := true
+	for _, c := range credentials {
+		r := checkUpstream(cfg, c.source)
+		fmt.Printf("%s: %s\n", c.name, r)
+		for _, h := range r.rateLimitHeaders() {
+			fmt.Printf("  %s\n", h)
+		}
+		ok = ok && r.ok()
+	}
+
+	if !ok {
+		return 1
+	}
+	return 0
+}
+
+// checkResult is the outcome of one call.
+type checkResult struct {
+	latency time.Duration
+	status  int
+	header  http.Header
+	err     error
+}
+
+func (r checkResult) ok() bool {
+	return r.err == nil && r.status == http.StatusOK
+}
+
+func (r checkResult) String() string {
+	var verdict string
+	switch {
+	case r.status == http.StatusOK && r.err == nil:
+		verdict = "authenticated"
+	case r.status == http.StatusUnauthorized || r.status == http.StatusForbidden:
+		verdict = "rejected"
+	case r.status == http.StatusTooManyRequests:
+		verdict = "throttled, authentication unknown"
+	case r.status == 0:
+		return fmt.Sprintf("failed after %s: %v", r.latency.Round(time.Millisecond), r.err)
+	case r.err != nil:
+		verdict = r.err.Error()
+	default:
+		verdict = "unexpected response"
+	}
+	return fmt.Sprintf("%s, HTTP %d in %s", verdict, r.status, r.latency.Round(time.Millisecond))
+}
+
+// rateLimitHeaders are the quota and Retry-After headers of the response.
+func (r checkResult) rateLimitHeaders() []string {
+	var headers []string
+	for name, values := range r.header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-ratelimit-") || strings.HasPrefix(lower, "ratelimit-") || lower == "retry-after" {
+			headers = append(headers, name+": "+strings.Join(values, ", "))
+		}
+	}
+	slices.Sort(headers)
+	return headers
+}
+
+func checkUpstream(cfg *config.Config, tokens abios.TokenSource) checkResult {
+	p := &probe{transport: http.DefaultTransport}
+	client := abios.NewClient(
+		abios.WithBaseURL(cfg.ApiBaseUrl),
+		abios.WithTokenSource(tokens),
+		abios.WithTimeout(cfg.ReqTimeout),
+		// one call, so a 429 is reported rather than waited out
+		abios.WithRetryPolicy(abios.RetryPolicy{MaxAttempts: 1}),
+		abios.WithTransport(p),
+	)
+
+	start := time.Now()
+	_, err := client.ListGames(context.Background(), abios.Query{}.Take(1))
+	latency := time.Since(start)
+
+	status, header := p.last()
+	if status == http.StatusTooManyRequests {
+		// the retry transport gives up with its own error; the status says it all
+		err = nil
+	}
+	return checkResult{latency: latency, status: status, header: header, err: err}
+}
+
+// probe keeps the status and headers of the last response.
+type probe struct {
+	transport http.RoundTripper
+
+	mu     sync.Mutex
+	status int
+	header http.Header
+}
+
+func (p *probe) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := p.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.status, p.header = resp.StatusCode, resp.Header.Clone()
+	p.mu.Unlock()
+
+	return resp, nil
+}
+
+func (p *probe) last() (int, http.Header) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.status, p.header
+}
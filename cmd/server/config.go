@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/benjaminmishra/abios-apis/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// runConfigValidate loads the configuration as serve would and lists every
+// problem with it, one per line.
+func runConfigValidate(args []string) int {
+	fs := newFlagSet("config validate")
+	flags := config.BindFlags(fs)
+	_ = fs.Parse(args)
+
+	if _, err := flags.Load(); err != nil {
+		printConfigError(err)
+		return 1
+	}
+
+	fmt.Println("configuration is valid")
+	return 0
+}
+
+// runConfigPrint prints the configuration serve would run with, every
+// layer applied, as a config file.
+func runConfigPrint(args []string) int {
+	fs := newFlagSet("config print")
+	redacted := fs.Bool("redacted", true, "print secrets as REDACTED")
+	flags := config.BindFlags(fs)
+	_ = fs.Parse(args)
+
+	cfg, err := flags.Load()
+	if err != nil {
+		printConfigError(err)
+		return 1
+	}
+
+	enc := yaml.NewEncoder(os.Stdout)
+	enc.SetIndent(2)
+	if err := enc.Encode(cfg.FileValues(*redacted)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := enc.Close(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func printConfigError(err error) {
+	var errs config.Errors
+	if !errors.As(err, &errs) {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "configuration is invalid, %d problem(s):\n", len(errs))
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+}
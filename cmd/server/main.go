@@ -1,51 +1,89 @@
+// Command server runs the Abios API wrapper and the tools to operate it:
+//
+//	server [serve] [-config config.yaml] [flags]
+//	server config validate [-config config.yaml] [flags]
+//	server config print [-redacted=false] [-config config.yaml] [flags]
+//	server check-upstream [-config config.yaml] [flags]
+//	server version
 package main
 
 import (
-	"context"
-	"log"
-	"net/http"
+	"flag"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
-
-	"github.com/benjaminmishra/abios-apis/internal/api"
-	"github.com/benjaminmishra/abios-apis/internal/config"
+	"runtime/debug"
+	"strings"
 )
 
-func main() {
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// version is set at build time with -ldflags "-X main.version=v1.2.3".
+var version = "dev"
+
+const usage = `Usage: server <command> [flags]
+
+Commands:
+  serve            run the server, the default when no command is given
+  config validate  check the configuration, listing every problem
+  config print     print the configuration as YAML, secrets redacted
+  check-upstream   make an authenticated call to Abios and report on it
+  version          print the version
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+Run "server <command> -h" for the flags of a command.
+`
 
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		log.Fatalf("failed to load config: %v", err)
+func main() {
+	args := os.Args[1:]
+
+	// flags without a command are served, as before there were commands
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		runServe(args)
+		return
 	}
 
-	apiServer := api.New(ctx, cfg)
+	switch cmd, rest := args[0], args[1:]; {
+	case cmd == "serve":
+		runServe(rest)
+	case cmd == "config" && len(rest) > 0 && rest[0] == "validate":
+		os.Exit(runConfigValidate(rest[1:]))
+	case cmd == "config" && len(rest) > 0 && rest[0] == "print":
+		os.Exit(runConfigPrint(rest[1:]))
+	case cmd == "check-upstream":
+		os.Exit(runCheckUpstream(rest))
+	case cmd == "version":
+		fmt.Println(versionString())
+	case cmd == "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", strings.Join(args[:min(len(args), 2)], " "), usage)
+		os.Exit(2)
+	}
+}
 
-	go func() {
-		if err := apiServer.Start(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("server error: %s", err)
-		}
-	}()
-
-	// wait for context cancellation or signal
-	select {
-	case <-quit:
-		log.Println("Shutdown signal received, initiating graceful shutdown...")
-		cancel()
-	case <-ctx.Done():
-		log.Println("Context done, initiating graceful shutdown...")
+// newFlagSet returns the flags of a command, with usage naming it.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: server %s [flags]\n\n", name)
+		fs.PrintDefaults()
 	}
+	return fs
+}
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// versionString is the version with the commit and Go version it was built
+// from.
+func versionString() string {
+	v := "abios-apis " + version
 
-	if err := apiServer.Stop(shutdownCtx); err != nil {
-		log.Printf("server shutdown error: %s", err)
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return v
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			v += " " + s.Value[:min(len(s.Value), 12)]
+		}
+		if s.Key == "vcs.modified" && s.Value == "true" {
+			v += "-dirty"
+		}
 	}
+	return v + " " + info.GoVersion
 }
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/config"
+)
+
+// configCheckInterval is how often the config file is checked for changes.
+const configCheckInterval = 2 * time.Second
+
+func runServe(args []string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	fs := newFlagSet("serve")
+	flags := config.BindFlags(fs)
+	_ = fs.Parse(args)
+
+	cfg, err := flags.Load()
+	if err != nil {
+		log.Fatalf("failed to load config: %v", err)
+	}
+
+	apiServer, err := api.New(ctx, cfg)
+	if err != nil {
+		log.Fatalf("failed to create server: %v", err)
+	}
+
+	go func() {
+		if err := apiServer.Start(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("server error: %s", err)
+		}
+	}()
+
+	go watchConfig(ctx, flags, apiServer)
+
+	// wait for context cancellation or signal
+	select {
+	case <-quit:
+		log.Println("Shutdown signal received, initiating graceful shutdown...")
+		cancel()
+	case <-ctx.Done():
+		log.Println("Context done, initiating graceful shutdown...")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := apiServer.Stop(shutdownCtx); err != nil {
+		log.Printf("server shutdown error: %s", err)
+	}
+}
+
+// watchConfig reloads the configuration into srv on SIGHUP and whenever the
+// config file changes. A configuration that fails to load is rejected and
+// the running one kept.
+func watchConfig(ctx context.Context, flags *config.Flags, srv *api.Server) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(configCheckInterval)
+	defer ticker.Stop()
+
+	modTime := fileModTime(flags.File())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Println("SIGHUP received, reloading config")
+		case <-ticker.C:
+			if flags.File() == "" {
+				continue
+			}
+			// a file being replaced may be missing for a moment; reloading
+			// then fails and is retried once it is back
+			m := fileModTime(flags.File())
+			if m.Equal(modTime) {
+				continue
+			}
+			modTime = m
+			log.Printf("config file %s changed, reloading config", flags.File())
+		}
+
+		cfg, err := flags.Load()
+		if err == nil {
+			err = srv.Reload(cfg)
+		}
+		if err != nil {
+			log.Printf("config reload rejected, keeping the current config: %v", err)
+		}
+	}
+}
+
+func fileModTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// cacheTTLReporter is implemented by live services that cache their
+// results, such as the one returned by service.NewCachedLiveService.
+type cacheTTLReporter interface {
+	CacheTTL() time.Duration
+}
+
+// cacheControl derives the Cache-Control header from the service cache TTL:
+// a client may reuse a response as long as the service would, and keep
+// serving it while revalidating for one more TTL. Without a cache clients
+// must revalidate every time, which is cheap thanks to ETags.
+func cacheControl(s any) string {
+	reporter, ok := s.(cacheTTLReporter)
+	if !ok || reporter.CacheTTL() < time.Second {
+		return "no-cache"
+	}
+
+	secs := int(reporter.CacheTTL() / time.Second)
+	return fmt.Sprintf("max-age=%d, stale-while-revalidate=%d", secs, secs)
+}
+
+// strongETag hashes the encoded body, so identical payloads always get
+// identical tags across requests and replicas.
+func strongETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches implements the If-None-Match check of RFC 9110, which uses
+// the weak comparison function.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
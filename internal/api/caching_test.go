@@ -0,0 +1,98 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// cachingLiveService reports a cache TTL like service.NewCachedLiveService.
+type cachingLiveService struct {
+	mockLiveService
+	ttl time.Duration
+}
+
+func (m *cachingLiveService) CacheTTL() time.Duration {
+	return m.ttl
+}
+
+func TestConditionalGet(t *testing.T) {
+	mockService := new(mockLiveService)
+	h := api.NewHandler(context.Background(), mockService)
+
+	mockService.On("GetLiveTeams", mock.Anything, []string(nil)).Return([]models.Team{
+		{ID: 1, Name: "Team 1"},
+	}, nil).Twice()
+	mockService.On("GetLiveTeams", mock.Anything, []string(nil)).Return([]models.Team{
+		{ID: 1, Name: "Team 1 Renamed"},
+	}, nil).Once()
+
+	w := httptest.NewRecorder()
+	h.GetLiveTeams(w, httptest.NewRequest(http.MethodGet, "/teams/live", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	etag := w.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Regexp(t, `^"[A-Za-z0-9_-]+"$`, etag, "expected a strong ETag")
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+	// unchanged data revalidates without a body
+	req := httptest.NewRequest(http.MethodGet, "/teams/live", nil)
+	req.Header.Set("If-None-Match", `"other", W/`+etag)
+	w = httptest.NewRecorder()
+	h.GetLiveTeams(w, req)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	// changed data gets a new representation and tag
+	req = httptest.NewRequest(http.MethodGet, "/teams/live", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	h.GetLiveTeams(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	assert.JSONEq(t, `{"data":[{"id":1,"name":"Team 1 Renamed"}]}`, w.Body.String())
+}
+
+func TestETagVariesWithRepresentation(t *testing.T) {
+	mockService := new(mockLiveService)
+	h := api.NewHandler(context.Background(), mockService)
+
+	mockService.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{
+		{ID: 1, Nickname: "a"},
+		{ID: 2, Nickname: "b"},
+	}, nil)
+
+	etags := map[string]bool{}
+	for _, target := range []string{"/players/live", "/players/live?limit=1", "/players/live?fields=id"} {
+		w := httptest.NewRecorder()
+		h.GetLivePlayers(w, httptest.NewRequest(http.MethodGet, target, nil))
+		etags[w.Header().Get("ETag")] = true
+	}
+
+	assert.Len(t, etags, 3)
+}
+
+func TestCacheControlFollowsServiceTTL(t *testing.T) {
+	mockService := &cachingLiveService{ttl: 5 * time.Second}
+	h := api.NewHandler(context.Background(), mockService)
+
+	mockService.On("GetLiveSeries", mock.Anything, []string(nil)).Return([]models.SeriesDetails{
+		{ID: 1, Title: "Series 1"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	h.GetLiveSeries(w, httptest.NewRequest(http.MethodGet, "/series/live", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "max-age=5, stale-while-revalidate=5", w.Header().Get("Cache-Control"))
+}
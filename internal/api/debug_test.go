@@ -0,0 +1,89 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaDriftReport(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 100, "title": "Team A"}]`))
+	}))
+	defer upstream.Close()
+
+	drift := abios.NewDriftDetector()
+	client := abios.NewClient(abios.WithBaseURL(upstream.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100), abios.WithDriftDetector(drift))
+	_, err := client.GetTeamsByID(context.Background(), []int{100})
+	require.NoError(t, err)
+
+	router := api.NewRouter(api.NewHandler(context.Background(), new(mockLiveService)), http.NotFoundHandler(), drift, false)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/schema-drift", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var report map[string]abios.EndpointDrift
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+	assert.Equal(t, map[string]int64{"name": 1}, report["/teams"].Missing)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var vars map[string]json.RawMessage
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&vars))
+	assert.Contains(t, vars, "abios_schema_drift")
+
+	doc := fetchOpenAPI(t, router)
+	assert.Contains(t, doc.Paths, "/debug/schema-drift")
+	assert.Contains(t, doc.Paths, "/debug/vars")
+}
+
+func TestDebugEndpointsAreOptIn(t *testing.T) {
+	cfg := reloadConfig("http://127.0.0.1:1")
+	srv, err := api.New(context.Background(), cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, serve(srv, "/debug/vars", 1))
+	assert.Equal(t, http.StatusNotFound, serve(srv, "/debug/schema-drift", 1))
+	assert.NotContains(t, fetchOpenAPI(t, srv.Handler()).Paths, "/debug/vars")
+
+	cfg.DebugEndpoints = true
+	srv, err = api.New(context.Background(), cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, serve(srv, "/debug/vars", 1))
+	assert.Equal(t, http.StatusOK, serve(srv, "/debug/schema-drift", 1))
+	assert.Contains(t, fetchOpenAPI(t, srv.Handler()).Paths, "/debug/vars")
+}
+
+func TestClientRateLimitIsPublished(t *testing.T) {
+	cfg := reloadConfig("http://127.0.0.1:1")
+	cfg.DebugEndpoints = true
+	cfg.AbiosRateLimitRPS, cfg.AbiosRateLimitBurst = 3, 6
+	srv, err := api.New(context.Background(), cfg)
+	require.NoError(t, err)
+
+	// reloads show too
+	reloaded := *cfg
+	reloaded.AbiosRateLimitRPS = 4
+	require.NoError(t, srv.Reload(&reloaded))
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var vars struct {
+		RateLimit map[string]float64 `json:"abios_client_rate_limit"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&vars))
+	assert.Equal(t, map[string]float64{"requests_per_sec": 4, "burst": 6}, vars.RateLimit)
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// NewCheckedRouter is NewRouter with every response checked against the
+// OpenAPI document, failing t on any mismatch.
+func NewCheckedRouter(t testing.TB, h *handler, graphqlHandler http.Handler) http.Handler {
+	routes, spec := allRoutes(h, graphqlHandler, nil, false)
+	return newMux(routes, func(rt route, next http.Handler) http.Handler {
+		return responseValidationMiddleware(next, func() (*operation, map[string]*jsonSchema) {
+			doc := spec.document()
+			return doc.Paths[rt.path][strings.ToLower(rt.method)], doc.Components.Schemas
+		}, func(err error) {
+			t.Errorf("contract violation: %v", err)
+		})
+	})
+}
+
+// SwaggerUIAssets are the files the Swagger UI page loads from /docs/.
+var SwaggerUIAssets = swaggerUIAssets
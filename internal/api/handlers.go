@@ -1,82 +1,145 @@
 package api
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 
+	"github.com/benjaminmishra/abios-apis/internal/models"
 	"github.com/benjaminmishra/abios-apis/internal/service"
 )
 
 type handler struct {
 	rootCtx     context.Context
 	liveService service.LiveService
+	negotiator  *Negotiator
 }
 
 func NewHandler(ctx context.Context, s service.LiveService) *handler {
 	return &handler{
 		rootCtx:     ctx,
 		liveService: s,
+		negotiator:  NewNegotiator(),
 	}
 }
 
-func (h *handler) GetLiveSeries(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-
-	data, err := h.liveService.GetLiveSeries(ctx)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+// Negotiator exposes the format and compression registry of the handler,
+// so additional encoders can be plugged in.
+func (h *handler) Negotiator() *Negotiator {
+	return h.negotiator
+}
 
-	if len(data) == 0 {
-		http.Error(w, "No live series found", http.StatusNotFound)
-		return
-	}
+var (
+	seriesSchema = newModelSchema(reflect.TypeFor[models.SeriesDetails]())
+	playerSchema = newModelSchema(reflect.TypeFor[models.Player]())
+	teamSchema   = newModelSchema(reflect.TypeFor[models.Team]())
+)
 
-	writeJSON(w, http.StatusOK, data)
+func (h *handler) GetLiveSeries(w http.ResponseWriter, r *http.Request) {
+	serveList(h, w, r, seriesSchema, "No live series found", h.liveService.GetLiveSeries)
 }
 
 func (h *handler) GetLivePlayers(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+	serveList(h, w, r, playerSchema, "No live players found", h.liveService.GetLivePlayers)
+}
+
+func (h *handler) GetLiveTeams(w http.ResponseWriter, r *http.Request) {
+	serveList(h, w, r, teamSchema, "No live teams found", h.liveService.GetLiveTeams)
+}
+
+// serveList runs the shared flow of the live list endpoints: parse the list
+// query, fetch from the service, then filter, sort, paginate and project the
+// result into the format the client negotiated.
+func serveList[T any](
+	h *handler,
+	w http.ResponseWriter,
+	r *http.Request,
+	schema *modelSchema,
+	notFound string,
+	fetch func(ctx context.Context, games []string) ([]T, error),
+) {
+	q, err := parseListQuery(r, schema)
+	if err != nil {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	data, err := h.liveService.GetLivePlayers(ctx)
+	data, err := fetch(r.Context(), parseGames(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeServiceError(w, r, err)
 		return
 	}
 
+	data = applyListQuery(data, q)
 	if len(data) == 0 {
-		http.Error(w, "No live players found", http.StatusNotFound)
+		writeProblem(w, r, http.StatusNotFound, notFound)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, data)
+	items, next := paginate(data, q)
+
+	p := Payload{Items: project(items, q), Columns: q.columns()}
+	if next != nil {
+		p.NextCursor = q.encodeCursor(next)
+		w.Header().Set("Link", nextLink(r, p.NextCursor))
+	}
+
+	w.Header().Set("Cache-Control", cacheControl(h.liveService))
+	h.negotiator.write(w, r, http.StatusOK, p)
 }
 
-func (h *handler) GetLiveTeams(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
+// parseGames reads the game filter, accepting both "?game=cs2,dota2" and
+// repeated "?game=cs2&game=dota2" forms.
+func parseGames(r *http.Request) []string {
+	var games []string
+	for _, v := range r.URL.Query()["game"] {
+		for _, g := range strings.Split(v, ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				games = append(games, g)
+			}
+		}
+	}
+	return games
+}
 
-	data, err := h.liveService.GetLiveTeams(ctx)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	if errors.Is(err, service.ErrUnknownGame) {
+		writeProblem(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	if len(data) == 0 {
-		http.Error(w, "No live teams found", http.StatusNotFound)
+	writeProblem(w, r, http.StatusInternalServerError, err.Error())
+}
+
+// writeJSON encodes data with a strong ETag.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, data any) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	writeJSON(w, http.StatusOK, data)
+	writeBody(w, r, status, "application/json", strongETag(buf.Bytes()), buf.Bytes())
 }
 
-func writeJSON(w http.ResponseWriter, status int, data any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
+// writeBody sends an encoded body under its ETag. When the request already
+// holds the current representation it answers 304 Not Modified instead.
+func writeBody(w http.ResponseWriter, r *http.Request, status int, contentType, etag string, body []byte) {
+	w.Header().Set("ETag", etag)
 
-	err := json.NewEncoder(w).Encode(data)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if status == http.StatusOK && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Del("Content-Encoding")
+		w.WriteHeader(http.StatusNotModified)
+		return
 	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
 }
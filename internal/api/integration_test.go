@@ -0,0 +1,250 @@
+//go:build integration
+
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/config"
+	"github.com/benjaminmishra/abios-apis/internal/fakeabios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const upstreamToken = "integration-secret"
+
+type integrationServer struct {
+	url    string
+	server *api.Server
+	fake   *fakeabios.Server
+	served chan error
+}
+
+// startServer boots the full server from api.New on ephemeral ports, with
+// the fake Abios behind it. edit adjusts the config before New sees it.
+func startServer(t *testing.T, opts fakeabios.Options, edit func(cfg *config.Config)) *integrationServer {
+	t.Helper()
+
+	if opts.Token == "" {
+		opts.Token = upstreamToken
+	}
+	fake := fakeabios.NewServer(fakeabios.DefaultDataset(), opts)
+	upstream := httptest.NewServer(fake)
+	t.Cleanup(upstream.Close)
+
+	cfg := &config.Config{
+		ApiBaseUrl:          upstream.URL,
+		Token:               upstreamToken,
+		ReqTimeout:          5 * time.Second,
+		AbiosRateLimitRPS:   100,
+		AbiosRateLimitBurst: 100,
+		RateLimitRPS:        100,
+		RateLimitBurst:      100,
+	}
+	if edit != nil {
+		edit(cfg)
+	}
+
+	srv, err := api.New(context.Background(), cfg)
+	require.NoError(t, err)
+
+	httpLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	grpcLis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := &integrationServer{
+		url:    "http://" + httpLis.Addr().String(),
+		server: srv,
+		fake:   fake,
+		served: make(chan error, 1),
+	}
+	go func() {
+		s.served <- srv.Serve(httpLis, grpcLis)
+	}()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Stop(ctx)
+	})
+
+	return s
+}
+
+func (s *integrationServer) get(t *testing.T, path string) (*http.Response, []byte) {
+	t.Helper()
+
+	resp, err := http.Get(s.url + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body json.RawMessage
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	return resp, body
+}
+
+func TestIntegrationAuthHeader(t *testing.T) {
+	t.Run("Injected", func(t *testing.T) {
+		s := startServer(t, fakeabios.Options{}, nil)
+
+		resp, body := s.get(t, "/series/live?game=cs2")
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		assert.Contains(t, string(body), "PGL Major Grand Final")
+	})
+
+	t.Run("Rejected Upstream", func(t *testing.T) {
+		s := startServer(t, fakeabios.Options{}, func(cfg *config.Config) { cfg.Token = "wrong" })
+
+		resp, _ := s.get(t, "/series/live")
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func TestIntegrationUpstreamRetry(t *testing.T) {
+	s := startServer(t, fakeabios.Options{}, nil)
+	s.fake.Inject(http.StatusTooManyRequests, 1)
+
+	resp, body := s.get(t, "/series/live")
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	// games, the retried games call and live series
+	assert.Equal(t, 3, s.fake.Requests())
+
+	// the game catalog is reused, so only live series are fetched again
+	resp, body = s.get(t, "/series/live")
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	assert.Equal(t, 4, s.fake.Requests())
+}
+
+func TestIntegrationUpstreamTimeout(t *testing.T) {
+	s := startServer(t, fakeabios.Options{Latency: 3 * time.Second}, func(cfg *config.Config) {
+		cfg.ReqTimeout = time.Second
+	})
+
+	start := time.Now()
+	resp, _ := s.get(t, "/series/live")
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestIntegrationRateLimiting(t *testing.T) {
+	t.Run("Upstream", func(t *testing.T) {
+		// the fake would answer 429 beyond 4 requests a second, so the
+		// client must hold back to 2
+		s := startServer(t, fakeabios.Options{RequestsPerSec: 4, Burst: 2}, func(cfg *config.Config) {
+			cfg.AbiosRateLimitRPS = 2
+			cfg.AbiosRateLimitBurst = 2
+		})
+
+		start := time.Now()
+		for range 3 {
+			resp, body := s.get(t, "/series/live")
+			require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+		}
+
+		// the game catalog once and live series three times: four upstream
+		// calls with two in the burst need a second more
+		assert.Equal(t, 4, s.fake.Requests())
+		assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+	})
+
+	t.Run("Inbound", func(t *testing.T) {
+		s := startServer(t, fakeabios.Options{}, func(cfg *config.Config) {
+			cfg.CacheTTL = time.Minute
+			cfg.RateLimitRPS = 2
+			cfg.RateLimitBurst = 2
+		})
+
+		var mu sync.Mutex
+		statuses := map[int]int{}
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Go(func() {
+				resp, err := http.Get(s.url + "/series/live")
+				if err != nil {
+					return
+				}
+				resp.Body.Close()
+
+				mu.Lock()
+				statuses[resp.StatusCode]++
+				mu.Unlock()
+			})
+		}
+		wg.Wait()
+
+		assert.Positive(t, statuses[http.StatusOK])
+		assert.Positive(t, statuses[http.StatusTooManyRequests])
+	})
+}
+
+func TestIntegrationPagination(t *testing.T) {
+	s := startServer(t, fakeabios.Options{}, func(cfg *config.Config) { cfg.CacheTTL = time.Minute })
+
+	var ids []int
+	path := "/players/live?limit=3"
+	for pages := 0; path != ""; pages++ {
+		require.Less(t, pages, 5, "pagination does not end")
+
+		resp, body := s.get(t, path)
+		require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+		var page struct {
+			Data []struct {
+				ID int `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &page))
+		assert.LessOrEqual(t, len(page.Data), 3)
+		for _, p := range page.Data {
+			ids = append(ids, p.ID)
+		}
+
+		path = ""
+		if link := resp.Header.Get("Link"); link != "" {
+			path = strings.TrimPrefix(strings.TrimSuffix(link, `>; rel="next"`), "<")
+		}
+	}
+
+	assert.Equal(t, []int{1101, 1102, 1201, 1202, 2101, 2102, 2201}, ids)
+}
+
+func TestIntegrationGracefulShutdown(t *testing.T) {
+	s := startServer(t, fakeabios.Options{Latency: 300 * time.Millisecond}, nil)
+
+	inFlight := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(s.url + "/teams/live")
+		if err != nil {
+			inFlight <- 0
+			return
+		}
+		resp.Body.Close()
+		inFlight <- resp.StatusCode
+	}()
+
+	// let the request reach the server before stopping it
+	require.Eventually(t, func() bool { return s.fake.Requests() > 0 }, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, s.server.Stop(ctx))
+
+	assert.Equal(t, http.StatusOK, <-inFlight)
+	assert.True(t, errors.Is(<-s.served, http.ErrServerClosed))
+
+	_, err := http.Get(s.url + "/teams/live")
+	assert.Error(t, err)
+}
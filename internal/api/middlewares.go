@@ -11,7 +11,7 @@ func rateLimitMiddleware(next http.Handler, limiter *rate.Limiter) http.Handler
 
 	httpHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !limiter.Allow() {
-			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			writeProblem(w, r, http.StatusTooManyRequests, "")
 			return
 		}
 
@@ -0,0 +1,321 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// responses smaller than this are not worth compressing
+const minCompressSize = 512
+
+// Payload is the format independent content of a list response.
+type Payload struct {
+	// Items holds the models, or maps of the selected fields.
+	Items []any
+	// Columns lists the dotted scalar fields present in the items, in
+	// display order, for tabular formats.
+	Columns    []string
+	NextCursor string
+}
+
+// Format renders a Payload as one media type.
+type Format struct {
+	MediaType string
+	Encode    func(w io.Writer, p Payload) error
+}
+
+// Compression is a content coding offered through Accept-Encoding.
+type Compression struct {
+	// Name is the Accept-Encoding token, e.g. "gzip".
+	Name      string
+	NewWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+// Negotiator picks the format and compression of a response from the
+// Accept and Accept-Encoding request headers. Formats and compressions are
+// preferred in registration order when the client has no preference.
+type Negotiator struct {
+	formats      []Format
+	compressions []Compression
+}
+
+// NewNegotiator returns a Negotiator with the built-in formats (JSON,
+// NDJSON, CSV, MessagePack) and compressions (zstd, br, gzip).
+func NewNegotiator() *Negotiator {
+	n := &Negotiator{}
+
+	n.RegisterFormat(Format{MediaType: "application/json", Encode: encodeJSON})
+	n.RegisterFormat(Format{MediaType: "application/x-ndjson", Encode: encodeNDJSON})
+	n.RegisterFormat(Format{MediaType: "text/csv", Encode: encodeCSV})
+	n.RegisterFormat(Format{MediaType: "application/msgpack", Encode: encodeMsgpack})
+
+	n.RegisterCompression(Compression{Name: "zstd", NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w)
+	}})
+	n.RegisterCompression(Compression{Name: "br", NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return brotli.NewWriter(w), nil
+	}})
+	n.RegisterCompression(Compression{Name: "gzip", NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	}})
+
+	return n
+}
+
+// RegisterFormat adds a format, replacing any with the same media type.
+func (n *Negotiator) RegisterFormat(f Format) {
+	for i, existing := range n.formats {
+		if existing.MediaType == f.MediaType {
+			n.formats[i] = f
+			return
+		}
+	}
+	n.formats = append(n.formats, f)
+}
+
+// RegisterCompression adds a compression, replacing any with the same name.
+func (n *Negotiator) RegisterCompression(c Compression) {
+	for i, existing := range n.compressions {
+		if existing.Name == c.Name {
+			n.compressions[i] = c
+			return
+		}
+	}
+	n.compressions = append(n.compressions, c)
+}
+
+// format returns the registered format the client accepts best.
+func (n *Negotiator) format(r *http.Request) (Format, bool) {
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return n.formats[0], true
+	}
+
+	ranges := parseQualityList(accept)
+
+	best, bestQ := -1, 0.0
+	for i, f := range n.formats {
+		if q := mediaRangeQuality(ranges, f.MediaType); q > bestQ {
+			best, bestQ = i, q
+		}
+	}
+	if best < 0 {
+		return Format{}, false
+	}
+	return n.formats[best], true
+}
+
+// compression returns the registered compression the client accepts best,
+// or nil to send the body as is.
+func (n *Negotiator) compression(r *http.Request) *Compression {
+	codings := parseQualityList(r.Header.Get("Accept-Encoding"))
+
+	var best *Compression
+	bestQ := 0.0
+	for i, c := range n.compressions {
+		q, ok := codings[c.Name]
+		if !ok {
+			q = codings["*"]
+		}
+		if q > bestQ {
+			best, bestQ = &n.compressions[i], q
+		}
+	}
+	return best
+}
+
+// write renders the payload in the negotiated format and compression.
+func (n *Negotiator) write(w http.ResponseWriter, r *http.Request, status int, p Payload) {
+	w.Header().Add("Vary", "Accept, Accept-Encoding")
+
+	format, ok := n.format(r)
+	if !ok {
+		types := make([]string, len(n.formats))
+		for i, f := range n.formats {
+			types[i] = f.MediaType
+		}
+		writeProblem(w, r, http.StatusNotAcceptable, "supported media types: "+strings.Join(types, ", "))
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := format.Encode(&buf, p); err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	body, etag := buf.Bytes(), strongETag(buf.Bytes())
+
+	if c := n.compression(r); c != nil && len(body) >= minCompressSize {
+		compressed, err := compress(c, body)
+		if err != nil {
+			writeProblem(w, r, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		// a strong ETag identifies the exact bytes, so each coding gets its own
+		body = compressed
+		etag = strings.TrimSuffix(etag, `"`) + "-" + c.Name + `"`
+		w.Header().Set("Content-Encoding", c.Name)
+	}
+
+	writeBody(w, r, status, format.MediaType, etag, body)
+}
+
+func compress(c *Compression, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	cw, err := c.NewWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := cw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parseQualityList parses headers such as Accept and Accept-Encoding into
+// their values and q weights. Media type parameters other than q are
+// dropped.
+func parseQualityList(header string) map[string]float64 {
+	out := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		value, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.EqualFold(k, "q") {
+				if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		out[value] = q
+	}
+	return out
+}
+
+// mediaRangeQuality returns the q weight of the most specific range that
+// matches the media type.
+func mediaRangeQuality(ranges map[string]float64, mediaType string) float64 {
+	if q, ok := ranges[mediaType]; ok {
+		return q
+	}
+
+	typ, _, _ := strings.Cut(mediaType, "/")
+	if q, ok := ranges[typ+"/*"]; ok {
+		return q
+	}
+
+	return ranges["*/*"]
+}
+
+func encodeJSON(w io.Writer, p Payload) error {
+	return json.NewEncoder(w).Encode(listResponse{Data: p.Items, NextCursor: p.NextCursor})
+}
+
+// encodeNDJSON writes one item per line. The next cursor is only available
+// from the Link header.
+func encodeNDJSON(w io.Writer, p Payload) error {
+	enc := json.NewEncoder(w)
+	for _, item := range p.Items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeMsgpack(w io.Writer, p Payload) error {
+	enc := msgpack.NewEncoder(w)
+	enc.SetCustomStructTag("json")
+	return enc.Encode(listResponse{Data: p.Items, NextCursor: p.NextCursor})
+}
+
+// encodeCSV writes a header row of the columns and one row per item, with
+// nested fields flattened into their dotted column.
+func encodeCSV(w io.Writer, p Payload) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(p.Columns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(p.Columns))
+	for _, item := range p.Items {
+		fields, err := flatten(item)
+		if err != nil {
+			return err
+		}
+		for i, column := range p.Columns {
+			record[i] = fields[column]
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// flatten maps the dotted path of every scalar in the JSON form of item to
+// its text.
+func flatten(item any) (map[string]string, error) {
+	b, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var tree map[string]any
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	out := map[string]string{}
+	var walk func(prefix string, v any) error
+	walk = func(prefix string, v any) error {
+		switch tv := v.(type) {
+		case map[string]any:
+			for k, child := range tv {
+				if err := walk(prefix+k+".", child); err != nil {
+					return err
+				}
+			}
+		case nil:
+		case string:
+			out[strings.TrimSuffix(prefix, ".")] = tv
+		case json.Number, bool:
+			out[strings.TrimSuffix(prefix, ".")] = fmt.Sprint(tv)
+		default:
+			return errors.New("csv: lists cannot be flattened into a column")
+		}
+		return nil
+	}
+
+	return out, walk("", tree)
+}
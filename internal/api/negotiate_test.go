@@ -0,0 +1,212 @@
+package api_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var negotiateSeries = []models.SeriesDetails{
+	{ID: 1, Title: "Major, Final", Game: &models.Game{ID: 5, Title: "Counter-Strike 2", Slug: "cs2"}},
+	{ID: 2, Title: "Showmatch"},
+}
+
+func negotiate(t *testing.T, h interface {
+	GetLiveSeries(http.ResponseWriter, *http.Request)
+}, target string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	h.GetLiveSeries(w, req)
+	return w
+}
+
+func seriesService(series []models.SeriesDetails) *mockLiveService {
+	mockService := new(mockLiveService)
+	mockService.On("GetLiveSeries", mock.Anything, []string(nil)).Return(series, nil)
+	return mockService
+}
+
+func TestContentNegotiation(t *testing.T) {
+	h := api.NewHandler(context.Background(), seriesService(negotiateSeries))
+
+	tests := []struct {
+		name         string
+		accept       string
+		target       string
+		expectedType string
+		expectedBody string
+	}{
+		{
+			name:         "Default JSON",
+			expectedType: "application/json",
+			expectedBody: `{"data":[{"id":1,"title":"Major, Final","game":{"id":5,"title":"Counter-Strike 2","slug":"cs2"}},{"id":2,"title":"Showmatch"}]}` + "\n",
+		},
+		{
+			name:         "NDJSON",
+			accept:       "application/x-ndjson",
+			expectedType: "application/x-ndjson",
+			expectedBody: `{"id":1,"title":"Major, Final","game":{"id":5,"title":"Counter-Strike 2","slug":"cs2"}}` + "\n" + `{"id":2,"title":"Showmatch"}` + "\n",
+		},
+		{
+			name:         "CSV Flattens Nested Fields",
+			accept:       "text/csv",
+			expectedType: "text/csv",
+			expectedBody: "id,title,game.id,game.title,game.slug\n1,\"Major, Final\",5,Counter-Strike 2,cs2\n2,Showmatch,,,\n",
+		},
+		{
+			name:         "CSV Follows Field Selection",
+			accept:       "text/csv",
+			target:       "/series/live?fields=game,id",
+			expectedType: "text/csv",
+			expectedBody: "game.id,game.title,game.slug,id\n5,Counter-Strike 2,cs2,1\n,,,2\n",
+		},
+		{
+			name:         "Quality Weights",
+			accept:       "application/json;q=0.5, text/*;q=0.9",
+			expectedType: "text/csv",
+		},
+		{
+			name:         "Wildcard",
+			accept:       "*/*",
+			expectedType: "application/json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := tt.target
+			if target == "" {
+				target = "/series/live"
+			}
+
+			w := negotiate(t, h, target, map[string]string{"Accept": tt.accept})
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Header().Values("Vary"), "Accept, Accept-Encoding")
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestMsgpackFormat(t *testing.T) {
+	h := api.NewHandler(context.Background(), seriesService(negotiateSeries))
+
+	w := negotiate(t, h, "/series/live?limit=1", map[string]string{"Accept": "application/msgpack"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+
+	var decoded struct {
+		Data []struct {
+			ID    int    `msgpack:"id"`
+			Title string `msgpack:"title"`
+		} `msgpack:"data"`
+		NextCursor string `msgpack:"next_cursor"`
+	}
+	require.NoError(t, msgpack.Unmarshal(w.Body.Bytes(), &decoded))
+	require.Len(t, decoded.Data, 1)
+	assert.Equal(t, "Major, Final", decoded.Data[0].Title)
+	assert.NotEmpty(t, decoded.NextCursor)
+}
+
+func TestNotAcceptable(t *testing.T) {
+	mockService := seriesService(negotiateSeries)
+	h := api.NewHandler(context.Background(), mockService)
+
+	w := negotiate(t, h, "/series/live", map[string]string{"Accept": "application/xml"})
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+}
+
+func TestCompression(t *testing.T) {
+	// enough series to pass the compression threshold
+	var series []models.SeriesDetails
+	for i := 1; i <= 50; i++ {
+		series = append(series, models.SeriesDetails{ID: i, Title: fmt.Sprintf("Series %d", i)})
+	}
+	h := api.NewHandler(context.Background(), seriesService(series))
+
+	plain := negotiate(t, h, "/series/live", nil)
+	require.Equal(t, http.StatusOK, plain.Code)
+	assert.Empty(t, plain.Header().Get("Content-Encoding"))
+
+	decoders := map[string]func(io.Reader) (io.Reader, error){
+		"gzip": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"br":   func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil },
+		"zstd": func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) },
+	}
+
+	for coding, decode := range decoders {
+		t.Run(coding, func(t *testing.T) {
+			w := negotiate(t, h, "/series/live", map[string]string{"Accept-Encoding": "identity, " + coding})
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, coding, w.Header().Get("Content-Encoding"))
+
+			etag := w.Header().Get("ETag")
+			assert.True(t, strings.HasSuffix(etag, "-"+coding+`"`), etag)
+			assert.NotEqual(t, plain.Header().Get("ETag"), etag)
+
+			r, err := decode(bytes.NewReader(w.Body.Bytes()))
+			require.NoError(t, err)
+			body, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, plain.Body.String(), string(body))
+
+			// revalidating the compressed representation
+			w = negotiate(t, h, "/series/live", map[string]string{"Accept-Encoding": coding, "If-None-Match": etag})
+			assert.Equal(t, http.StatusNotModified, w.Code)
+		})
+	}
+
+	t.Run("Preference", func(t *testing.T) {
+		w := negotiate(t, h, "/series/live", map[string]string{"Accept-Encoding": "gzip;q=0.8, br;q=1.0, zstd;q=0"})
+		assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("Small Bodies Stay Uncompressed", func(t *testing.T) {
+		w := negotiate(t, h, "/series/live?limit=1", map[string]string{"Accept-Encoding": "gzip"})
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+	})
+}
+
+func TestRegisterFormat(t *testing.T) {
+	h := api.NewHandler(context.Background(), seriesService(negotiateSeries))
+
+	h.Negotiator().RegisterFormat(api.Format{
+		MediaType: "text/plain",
+		Encode: func(w io.Writer, p api.Payload) error {
+			for _, item := range p.Items {
+				fmt.Fprintln(w, item.(models.SeriesDetails).Title)
+			}
+			return nil
+		},
+	})
+
+	w := negotiate(t, h, "/series/live", map[string]string{"Accept": "text/plain"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Equal(t, "Major, Final\nShowmatch\n", w.Body.String())
+}
@@ -0,0 +1,369 @@
+package api
+
+import (
+	"cmp"
+	"embed"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+const openAPIVersion = "3.1.0"
+
+// The OpenAPI document is generated from the route table, so every route
+// the mux serves is described and nothing else is. Models are described by
+// reflecting over their JSON fields.
+
+type openAPIDocument struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       openAPIInfo                      `json:"info"`
+	Paths      map[string]map[string]*operation `json:"paths"`
+	Components openAPIComponents                `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*jsonSchema `json:"schemas"`
+}
+
+type operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []*parameter        `json:"parameters,omitempty"`
+	RequestBody *requestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type parameter struct {
+	Name        string      `json:"name"`
+	In          string      `json:"in"`
+	Description string      `json:"description,omitempty"`
+	Required    bool        `json:"required,omitempty"`
+	Style       string      `json:"style,omitempty"`
+	Explode     *bool       `json:"explode,omitempty"`
+	Schema      *jsonSchema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Headers     map[string]header    `json:"headers,omitempty"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type header struct {
+	Description string      `json:"description,omitempty"`
+	Schema      *jsonSchema `json:"schema"`
+}
+
+type mediaType struct {
+	Schema *jsonSchema `json:"schema"`
+}
+
+// jsonSchema is the subset of JSON Schema 2020-12 the document uses.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Enum                 []any                  `json:"enum,omitempty"`
+	Default              any                    `json:"default,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Maximum              *int                   `json:"maximum,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+}
+
+// specBuilder collects the component schemas referenced while the
+// operations are described.
+type specBuilder struct {
+	schemas map[string]*jsonSchema
+}
+
+func newOpenAPIDocument(routes []route) *openAPIDocument {
+	b := &specBuilder{schemas: map[string]*jsonSchema{}}
+
+	doc := &openAPIDocument{
+		OpenAPI: openAPIVersion,
+		Info: openAPIInfo{
+			Title:       "Abios API Wrapper",
+			Version:     "1.0.0",
+			Description: "Curated live esports series, players and teams from the Abios Atlas API.",
+		},
+		Paths: map[string]map[string]*operation{},
+	}
+
+	for _, rt := range routes {
+		if doc.Paths[rt.path] == nil {
+			doc.Paths[rt.path] = map[string]*operation{}
+		}
+		doc.Paths[rt.path][strings.ToLower(rt.method)] = rt.describe(b)
+	}
+
+	doc.Components.Schemas = b.schemas
+	return doc
+}
+
+// schemaOf describes the JSON form of t. Structs become components and are
+// referenced by name.
+func (b *specBuilder) schemaOf(t reflect.Type) *jsonSchema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.schemaOf(t.Elem())
+	case reflect.String:
+		return &jsonSchema{Type: "string"}
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &jsonSchema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return b.component(t)
+	default:
+		return &jsonSchema{}
+	}
+}
+
+func (b *specBuilder) component(t reflect.Type) *jsonSchema {
+	name := componentName(t)
+	ref := &jsonSchema{Ref: "#/components/schemas/" + name}
+	if _, ok := b.schemas[name]; ok {
+		return ref
+	}
+
+	// registered before the fields so recursive types terminate
+	s := &jsonSchema{Type: "object", Properties: map[string]*jsonSchema{}}
+	b.schemas[name] = s
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if name := jsonName(f); name != "" {
+			s.Properties[name] = b.schemaOf(f.Type)
+		}
+	}
+
+	return ref
+}
+
+func componentName(t reflect.Type) string {
+	r := []rune(t.Name())
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func (b *specBuilder) problemSchema() *jsonSchema {
+	t := reflect.TypeFor[problem]()
+	ref := b.schemaOf(t)
+	b.schemas[componentName(t)].Required = []string{"type", "title", "status"}
+	return ref
+}
+
+// problemResponse describes an RFC 9457 problem details response.
+func (b *specBuilder) problemResponse(description string) response {
+	return response{
+		Description: description,
+		Content:     map[string]mediaType{problemContentType: {Schema: b.problemSchema()}},
+	}
+}
+
+// listOperation describes a live list endpoint serving items of the given
+// model in every format registered on the negotiator.
+func (b *specBuilder) listOperation(n *Negotiator, id, summary string, item reflect.Type, schema *modelSchema) *operation {
+	itemSchema := b.schemaOf(item)
+
+	// the typed form of listResponse
+	list := &jsonSchema{
+		Type: "object",
+		Properties: map[string]*jsonSchema{
+			"data":        {Type: "array", Items: itemSchema},
+			"next_cursor": {Type: "string", Description: "Cursor of the next page, absent on the last page."},
+		},
+		Required: []string{"data"},
+	}
+	listName := componentName(item) + "List"
+	b.schemas[listName] = list
+	listRef := &jsonSchema{Ref: "#/components/schemas/" + listName}
+
+	content := map[string]mediaType{}
+	for _, f := range n.formats {
+		switch f.MediaType {
+		case "application/json", "application/msgpack":
+			content[f.MediaType] = mediaType{Schema: listRef}
+		case "application/x-ndjson":
+			content[f.MediaType] = mediaType{Schema: itemSchema}
+		case "text/csv":
+			content[f.MediaType] = mediaType{Schema: &jsonSchema{Type: "string"}}
+		default:
+			content[f.MediaType] = mediaType{Schema: &jsonSchema{}}
+		}
+	}
+
+	return &operation{
+		OperationID: id,
+		Summary:     summary,
+		Tags:        []string{"live"},
+		Parameters:  listParameters(schema),
+		Responses: map[string]response{
+			"200": {
+				Description: "A page of items.",
+				Headers: map[string]header{
+					"ETag":          {Description: "Strong validator of the representation.", Schema: &jsonSchema{Type: "string"}},
+					"Cache-Control": {Schema: &jsonSchema{Type: "string"}},
+					"Link":          {Description: `Link to the next page with rel="next".`, Schema: &jsonSchema{Type: "string"}},
+				},
+				Content: content,
+			},
+			"304": {Description: "The representation matches If-None-Match."},
+			"400": b.problemResponse("Invalid query parameters, or an unknown game."),
+			"404": b.problemResponse("Nothing is live, or nothing matches the filter."),
+			"406": b.problemResponse("None of the accepted media types is supported."),
+			"429": b.problemResponse("The inbound rate limit is exceeded."),
+			"500": b.problemResponse("The Abios API failed."),
+		},
+	}
+}
+
+// listParameters are the query parameters shared by the list endpoints.
+// Their schemas are also what inbound requests are checked against.
+func listParameters(schema *modelSchema) []*parameter {
+	fields := strings.Join(schema.paths, ", ")
+
+	top := make([]any, 0, len(schema.top))
+	for name := range schema.top {
+		top = append(top, name)
+	}
+	slices.SortFunc(top, func(a, b any) int { return cmp.Compare(schema.top[a.(string)], schema.top[b.(string)]) })
+
+	return []*parameter{
+		{
+			Name:        "game",
+			In:          "query",
+			Description: "Abios game slugs to restrict the result to, repeated or comma separated, e.g. cs2,dota2.",
+			Style:       "form",
+			Explode:     ptr(true),
+			Schema:      &jsonSchema{Type: "array", Items: &jsonSchema{Type: "string"}},
+		},
+		{
+			Name:        "filter",
+			In:          "query",
+			Description: "Comma separated conditions <field><op><value>, op one of = != < <= > >= ~. Fields: " + fields + ".",
+			Schema:      &jsonSchema{Type: "string"},
+		},
+		{
+			Name:        "sort",
+			In:          "query",
+			Description: "Comma separated fields, prefixed with - for descending. Fields: " + fields + ".",
+			Schema:      &jsonSchema{Type: "string"},
+		},
+		{
+			Name:        "fields",
+			In:          "query",
+			Description: "Comma separated top-level fields to keep in each item.",
+			Style:       "form",
+			Explode:     ptr(false),
+			Schema:      &jsonSchema{Type: "array", Items: &jsonSchema{Type: "string", Enum: top}},
+		},
+		{
+			Name:        "limit",
+			In:          "query",
+			Description: "Page size.",
+			Schema:      &jsonSchema{Type: "integer", Minimum: ptr(1), Maximum: ptr(maxPageLimit), Default: defaultPageLimit},
+		},
+		{
+			Name:        "cursor",
+			In:          "query",
+			Description: "The next_cursor of the previous page.",
+			Schema:      &jsonSchema{Type: "string"},
+		},
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// openAPIHandler serves the document of the routes it is given. The document
+// is generated on first use, so formats registered on the negotiator after
+// the routes were set up are included.
+type openAPIHandler struct {
+	routes []route
+
+	once sync.Once
+	doc  *openAPIDocument
+	body []byte
+	err  error
+}
+
+func (h *openAPIHandler) document() *openAPIDocument {
+	h.once.Do(func() {
+		h.doc = newOpenAPIDocument(h.routes)
+		h.body, h.err = json.MarshalIndent(h.doc, "", "  ")
+	})
+	return h.doc
+}
+
+func (h *openAPIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.document()
+	if h.err != nil {
+		writeProblem(w, r, http.StatusInternalServerError, h.err.Error())
+		return
+	}
+
+	writeBody(w, r, http.StatusOK, "application/json", strongETag(h.body), h.body)
+}
+
+//go:generate sh swaggerui/vendor.sh
+
+// swaggerUI holds the Swagger UI page and its assets. swagger-ui-dist is
+// vendored by swaggerui/vendor.sh, so /docs loads nothing from elsewhere.
+//
+//go:embed swaggerui
+var swaggerUI embed.FS
+
+// swaggerUIAssets are the files the page loads from /docs/, by content type.
+var swaggerUIAssets = map[string]string{
+	"swagger-ui.css":         "text/css; charset=utf-8",
+	"swagger-ui-bundle.js":   "text/javascript; charset=utf-8",
+	"swagger-initializer.js": "text/javascript; charset=utf-8",
+}
+
+// serveSwaggerUI serves a Swagger UI page rendering /openapi.json.
+func serveSwaggerUI(w http.ResponseWriter, r *http.Request) {
+	serveSwaggerUIFile(w, r, "index.html", "text/html; charset=utf-8")
+}
+
+func serveSwaggerUIAsset(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("asset")
+	serveSwaggerUIFile(w, r, name, swaggerUIAssets[name])
+}
+
+func serveSwaggerUIFile(w http.ResponseWriter, r *http.Request, name, contentType string) {
+	body, err := swaggerUI.ReadFile("swaggerui/" + name)
+	if err != nil {
+		writeProblem(w, r, http.StatusNotFound, name+" is missing from the build")
+		return
+	}
+
+	writeBody(w, r, http.StatusOK, contentType, strongETag(body), body)
+}
@@ -0,0 +1,195 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type openAPISchema struct {
+	Ref        string                    `json:"$ref"`
+	Type       string                    `json:"type"`
+	Items      *openAPISchema            `json:"items"`
+	Properties map[string]*openAPISchema `json:"properties"`
+}
+
+type openAPIDoc struct {
+	OpenAPI string `json:"openapi"`
+	Paths   map[string]map[string]struct {
+		OperationID string `json:"operationId"`
+		Parameters  []struct {
+			Name string `json:"name"`
+		} `json:"parameters"`
+		Responses map[string]struct {
+			Content map[string]struct {
+				Schema *openAPISchema `json:"schema"`
+			} `json:"content"`
+		} `json:"responses"`
+	} `json:"paths"`
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+func (d *openAPIDoc) resolve(t *testing.T, s *openAPISchema) *openAPISchema {
+	t.Helper()
+
+	if s.Ref == "" {
+		return s
+	}
+	resolved, ok := d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	require.True(t, ok, "unresolved reference %s", s.Ref)
+	return resolved
+}
+
+func liveServiceWithData() *mockLiveService {
+	mockService := new(mockLiveService)
+	mockService.On("GetLiveSeries", mock.Anything, []string(nil)).Return([]models.SeriesDetails{
+		{ID: 1, Title: "Series 1", Game: &models.Game{ID: 5, Title: "Counter-Strike 2", Slug: "cs2"}},
+	}, nil)
+	mockService.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{{ID: 1, Nickname: "p1"}}, nil)
+	mockService.On("GetLiveTeams", mock.Anything, []string(nil)).Return([]models.Team{{ID: 1, Name: "Team A"}}, nil)
+	return mockService
+}
+
+func fetchOpenAPI(t *testing.T, router http.Handler) *openAPIDoc {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var doc openAPIDoc
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&doc))
+	return &doc
+}
+
+func TestOpenAPIDescribesEveryRoute(t *testing.T) {
+	h := api.NewHandler(context.Background(), liveServiceWithData())
+	router := api.NewRouter(h, http.NotFoundHandler(), nil, false)
+
+	doc := fetchOpenAPI(t, router)
+	assert.Equal(t, "3.1.0", doc.OpenAPI)
+
+	expected := map[string][]string{
+		"/series/live":  {"get"},
+		"/players/live": {"get"},
+		"/teams/live":   {"get"},
+		"/graphql":      {"get", "post"},
+		"/openapi.json": {"get"},
+	}
+	require.ElementsMatch(t, slices.Collect(maps.Keys(expected)), slices.Collect(maps.Keys(doc.Paths)))
+
+	for path, methods := range expected {
+		assert.ElementsMatch(t, methods, slices.Collect(maps.Keys(doc.Paths[path])), path)
+		for method, op := range doc.Paths[path] {
+			assert.NotEmpty(t, op.OperationID, "%s %s", method, path)
+		}
+	}
+
+	var params []string
+	for _, p := range doc.Paths["/series/live"]["get"].Parameters {
+		params = append(params, p.Name)
+	}
+	assert.ElementsMatch(t, []string{"game", "filter", "sort", "fields", "limit", "cursor"}, params)
+}
+
+// The documented item schemas must match what the handlers actually encode.
+func TestOpenAPIMatchesResponses(t *testing.T) {
+	h := api.NewHandler(context.Background(), liveServiceWithData())
+	router := api.NewRouter(h, http.NotFoundHandler(), nil, false)
+	doc := fetchOpenAPI(t, router)
+
+	for _, path := range []string{"/series/live", "/players/live", "/teams/live"} {
+		t.Run(path, func(t *testing.T) {
+			content := doc.Paths[path]["get"].Responses["200"].Content
+			require.Contains(t, content, "application/json")
+
+			list := doc.resolve(t, content["application/json"].Schema)
+			require.Contains(t, list.Properties, "data")
+			item := doc.resolve(t, list.Properties["data"].Items)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			var body struct {
+				Data []map[string]any `json:"data"`
+			}
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+			require.Len(t, body.Data, 1)
+
+			assert.ElementsMatch(t, slices.Collect(maps.Keys(item.Properties)), slices.Collect(maps.Keys(body.Data[0])))
+		})
+	}
+}
+
+func TestOpenAPIListsRegisteredFormats(t *testing.T) {
+	h := api.NewHandler(context.Background(), liveServiceWithData())
+	router := api.NewRouter(h, http.NotFoundHandler(), nil, false)
+
+	// registered after the routes, still documented
+	h.Negotiator().RegisterFormat(api.Format{MediaType: "text/plain", Encode: func(io.Writer, api.Payload) error { return nil }})
+
+	doc := fetchOpenAPI(t, router)
+	content := doc.Paths["/teams/live"]["get"].Responses["200"].Content
+	assert.ElementsMatch(t,
+		[]string{"application/json", "application/x-ndjson", "text/csv", "application/msgpack", "text/plain"},
+		slices.Collect(maps.Keys(content)))
+}
+
+func TestSwaggerUI(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		h := api.NewHandler(context.Background(), liveServiceWithData())
+		router := api.NewRouter(h, http.NotFoundHandler(), nil, enabled)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+
+		doc := fetchOpenAPI(t, router)
+		if !enabled {
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.NotContains(t, doc.Paths, "/docs")
+			continue
+		}
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		page := w.Body.String()
+		assert.NotContains(t, page, "://", "the page loads nothing from other origins")
+		assert.Contains(t, doc.Paths, "/docs")
+		assert.Contains(t, doc.Paths, "/docs/{asset}")
+
+		// every asset the page loads is embedded
+		for name, contentType := range api.SwaggerUIAssets {
+			assert.Contains(t, page, `"/docs/`+name+`"`)
+
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/"+name, nil))
+			assert.Equal(t, http.StatusOK, w.Code, name)
+			assert.Equal(t, contentType, w.Header().Get("Content-Type"), name)
+			assert.NotEmpty(t, w.Body.Bytes(), name)
+		}
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/swagger-initializer.js", nil))
+		assert.Contains(t, w.Body.String(), `url: "/openapi.json"`)
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs/vendor.sh", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// listResponse is the envelope every list endpoint responds with.
+type listResponse struct {
+	Data       any    `json:"data"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// cursor is the decoded form of the opaque "cursor" parameter. It holds the
+// sort key of the last item served, so the next page starts right after it
+// even when items were added or removed in between. The sort spec is kept
+// to reject cursors replayed against a different order.
+type cursor struct {
+	Sort string `json:"s"`
+	Key  []any  `json:"k"`
+}
+
+func (q *listQuery) parsePage(query url.Values) error {
+	q.limit = defaultPageLimit
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return fmt.Errorf("invalid limit: must be an integer between 1 and %d", maxPageLimit)
+		}
+		q.limit = limit
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		after, err := q.decodeCursor(raw)
+		if err != nil {
+			return err
+		}
+		q.after = after
+	}
+
+	return nil
+}
+
+func (q *listQuery) encodeCursor(key []any) string {
+	b, _ := json.Marshal(cursor{Sort: q.sortSpec(), Key: key})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (q *listQuery) decodeCursor(raw string) ([]any, error) {
+	b, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var c cursor
+	if err := dec.Decode(&c); err != nil || len(c.Key) != len(q.sort) {
+		return nil, errInvalidCursor
+	}
+	if c.Sort != q.sortSpec() {
+		return nil, fmt.Errorf("%w: it was issued for sort %q", errInvalidCursor, c.Sort)
+	}
+
+	// JSON loses the Go types, so restore them from the sort fields
+	key := make([]any, len(c.Key))
+	for i, v := range c.Key {
+		if v == nil {
+			continue
+		}
+
+		kind := q.sort[i].info.kind
+		var ok bool
+		switch tv := v.(type) {
+		case json.Number:
+			key[i], err = parseValue(tv.String(), kind)
+			ok = err == nil && kind != reflect.String && kind != reflect.Bool
+		case string:
+			key[i], ok = tv, kind == reflect.String
+		case bool:
+			key[i], ok = tv, kind == reflect.Bool
+		}
+		if !ok {
+			return nil, errInvalidCursor
+		}
+	}
+
+	return key, nil
+}
+
+// paginate cuts the page out of sorted items and returns the sort key to
+// resume from, or nil when this is the last page.
+func paginate[T any](items []T, q *listQuery) ([]T, []any) {
+	start := 0
+	if q.after != nil {
+		start = len(items)
+		for i, item := range items {
+			if q.compareKeys(q.key(reflect.ValueOf(item)), q.after) > 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	end := min(start+q.limit, len(items))
+	if end == len(items) {
+		return items[start:end], nil
+	}
+
+	return items[start:end], q.key(reflect.ValueOf(items[end-1]))
+}
+
+// nextLink builds the RFC 8288 Link header value pointing at the next page,
+// keeping every other parameter of the current request.
+func nextLink(r *http.Request, next string) string {
+	query := r.URL.Query()
+	query.Set("cursor", next)
+
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="next"`, u.String())
+}
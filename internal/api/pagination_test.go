@@ -0,0 +1,136 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type playersPage struct {
+	Data       []models.Player `json:"data"`
+	NextCursor string          `json:"next_cursor"`
+}
+
+func getPlayersPage(t *testing.T, h http.HandlerFunc, target string) (playersPage, *http.Response) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	h(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	var page playersPage
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&page))
+	}
+	return page, w.Result()
+}
+
+func TestPagination(t *testing.T) {
+	mockService := new(mockLiveService)
+	h := api.NewHandler(context.Background(), mockService)
+
+	// deliberately unordered, as returned upstream
+	mockService.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{
+		{ID: 4, Nickname: "d"},
+		{ID: 2, Nickname: "b"},
+		{ID: 5, Nickname: "e"},
+		{ID: 1, Nickname: "a"},
+		{ID: 3, Nickname: "c"},
+	}, nil)
+
+	var ids []int
+	target := "/players/live?limit=2"
+	for pages := 0; target != ""; pages++ {
+		require.Less(t, pages, 3, "expected three pages")
+
+		page, resp := getPlayersPage(t, h.GetLivePlayers, target)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		for _, p := range page.Data {
+			ids = append(ids, p.ID)
+		}
+
+		target = ""
+		if page.NextCursor != "" {
+			next := "/players/live?" + url.Values{"cursor": {page.NextCursor}, "limit": {"2"}}.Encode()
+			assert.Equal(t, "<"+next+`>; rel="next"`, resp.Header.Get("Link"))
+			target = next
+		} else {
+			assert.Empty(t, resp.Header.Get("Link"))
+		}
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+}
+
+func TestPaginationFollowsSort(t *testing.T) {
+	mockService := new(mockLiveService)
+	h := api.NewHandler(context.Background(), mockService)
+
+	mockService.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{
+		{ID: 1, Nickname: "same"},
+		{ID: 2, Nickname: "same"},
+		{ID: 3, Nickname: "other"},
+	}, nil)
+
+	first, _ := getPlayersPage(t, h.GetLivePlayers, "/players/live?sort=-nick_name&limit=1")
+	require.Len(t, first.Data, 1)
+	assert.Equal(t, 1, first.Data[0].ID)
+
+	second, _ := getPlayersPage(t, h.GetLivePlayers, "/players/live?sort=-nick_name&limit=1&cursor="+first.NextCursor)
+	require.Len(t, second.Data, 1)
+	assert.Equal(t, 2, second.Data[0].ID)
+
+	// the cursor is bound to the order it was issued for
+	_, resp := getPlayersPage(t, h.GetLivePlayers, "/players/live?sort=nick_name&limit=1&cursor="+first.NextCursor)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestPaginationSurvivesChangingData(t *testing.T) {
+	mockService := new(mockLiveService)
+	h := api.NewHandler(context.Background(), mockService)
+
+	mockService.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{
+		{ID: 10}, {ID: 20}, {ID: 30},
+	}, nil).Once()
+	mockService.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{
+		{ID: 5}, {ID: 30}, {ID: 40},
+	}, nil).Once()
+
+	first, _ := getPlayersPage(t, h.GetLivePlayers, "/players/live?limit=2")
+	assert.Equal(t, []models.Player{{ID: 10}, {ID: 20}}, first.Data)
+
+	// 20 went away and 5 appeared before it; the next page still resumes after 20
+	second, _ := getPlayersPage(t, h.GetLivePlayers, "/players/live?limit=2&cursor="+first.NextCursor)
+	assert.Equal(t, []models.Player{{ID: 30}, {ID: 40}}, second.Data)
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestPaginationInvalidParams(t *testing.T) {
+	for _, target := range []string{
+		"/players/live?limit=0",
+		"/players/live?limit=201",
+		"/players/live?limit=ten",
+		"/players/live?cursor=not-a-cursor",
+		"/players/live?cursor=e30", // valid base64 of "{}"
+	} {
+		t.Run(target, func(t *testing.T) {
+			mockService := new(mockLiveService)
+			h := api.NewHandler(context.Background(), mockService)
+
+			_, resp := getPlayersPage(t, h.GetLivePlayers, target)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.Equal(t, "application/problem+json", resp.Header.Get("Content-Type"))
+
+			mockService.AssertNotCalled(t, "GetLivePlayers", mock.Anything, mock.Anything)
+		})
+	}
+}
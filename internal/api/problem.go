@@ -0,0 +1,37 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const problemContentType = "application/problem+json"
+
+// problem is an RFC 9457 problem details body.
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Errors lists the individual violations of a rejected request.
+	Errors []violation `json:"errors,omitempty"`
+}
+
+// writeProblem answers with a problem document of status. violations are
+// the individual problems of a rejected request, if any.
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string, violations ...violation) {
+	p := problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Errors:   violations,
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(p)
+}
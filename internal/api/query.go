@@ -0,0 +1,539 @@
+package api
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// The list query language understood by the list endpoints:
+//
+//	?filter=title~major,id>=10,game.slug={cs2,dota2}
+//	?sort=title,-id
+//	?fields=id,title
+//
+// A filter is a comma separated list of conditions that must all hold. Each
+// condition is "<field><op><value>" where op is one of = != < <= > >= or ~
+// (case-insensitive substring). A value wrapped in braces is a set, usable
+// with = and !=. Values may be double quoted to include commas or braces.
+// Fields are the JSON names of the model, dotted for nested objects.
+
+type operator string
+
+const (
+	opEq       operator = "="
+	opNotEq    operator = "!="
+	opLess     operator = "<"
+	opLessEq   operator = "<="
+	opGreater  operator = ">"
+	opGreatEq  operator = ">="
+	opContains operator = "~"
+)
+
+// longest operators first so "<=" is not read as "<"
+var operators = []operator{opNotEq, opLessEq, opGreatEq, opEq, opLess, opGreater, opContains}
+
+type condition struct {
+	field  string
+	info   fieldInfo
+	op     operator
+	values []any
+}
+
+type sortKey struct {
+	field string
+	info  fieldInfo
+	desc  bool
+}
+
+type listQuery struct {
+	filter []condition
+	sort   []sortKey
+	fields []string
+	limit  int
+	after  []any
+	schema *modelSchema
+}
+
+// fieldInfo locates a scalar field within a model.
+type fieldInfo struct {
+	index []int
+	kind  reflect.Kind
+}
+
+// modelSchema describes the JSON fields of a model that can be queried.
+type modelSchema struct {
+	scalars map[string]fieldInfo
+	top     map[string]int
+	// paths lists the scalar fields in declaration order
+	paths []string
+}
+
+func newModelSchema(t reflect.Type) *modelSchema {
+	s := &modelSchema{
+		scalars: map[string]fieldInfo{},
+		top:     map[string]int{},
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			s.top[name] = i
+		}
+	}
+	s.collect(t, "", nil)
+
+	return s
+}
+
+func (s *modelSchema) collect(t reflect.Type, prefix string, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+
+		path := prefix + name
+		idx := append(slices.Clone(index), i)
+
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		switch ft.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+			s.scalars[path] = fieldInfo{index: idx, kind: ft.Kind()}
+			s.paths = append(s.paths, path)
+		case reflect.Struct:
+			s.collect(ft, path+".", idx)
+		}
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
+
+func (s *modelSchema) scalar(field string) (fieldInfo, error) {
+	info, ok := s.scalars[field]
+	if !ok {
+		return fieldInfo{}, fmt.Errorf("unknown field %q", field)
+	}
+	return info, nil
+}
+
+func parseListQuery(r *http.Request, schema *modelSchema) (*listQuery, error) {
+	query := r.URL.Query()
+	q := &listQuery{schema: schema}
+
+	if raw := query.Get("filter"); raw != "" {
+		filter, err := parseFilter(raw, schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter: %w", err)
+		}
+		q.filter = filter
+	}
+
+	if raw := query.Get("sort"); raw != "" {
+		sort, err := parseSort(raw, schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sort: %w", err)
+		}
+		q.sort = sort
+	}
+	q.sort = withIDTiebreak(q.sort, schema)
+
+	if raw := query.Get("fields"); raw != "" {
+		fields, err := parseFields(raw, schema)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fields: %w", err)
+		}
+		q.fields = fields
+	}
+
+	if err := q.parsePage(query); err != nil {
+		return nil, err
+	}
+
+	return q, nil
+}
+
+func parseFilter(raw string, schema *modelSchema) ([]condition, error) {
+	terms, err := splitTopLevel(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]condition, 0, len(terms))
+	for _, term := range terms {
+		c, err := parseCondition(term, schema)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+	}
+
+	return conditions, nil
+}
+
+func parseCondition(term string, schema *modelSchema) (condition, error) {
+	end := strings.IndexFunc(term, func(r rune) bool {
+		return !(r == '_' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end <= 0 {
+		return condition{}, fmt.Errorf("expected <field><op><value>, got %q", term)
+	}
+
+	field := term[:end]
+	info, err := schema.scalar(field)
+	if err != nil {
+		return condition{}, err
+	}
+
+	rest := term[end:]
+	var op operator
+	for _, candidate := range operators {
+		if strings.HasPrefix(rest, string(candidate)) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return condition{}, fmt.Errorf("missing operator after %q", field)
+	}
+
+	rawValue := rest[len(op):]
+	var rawValues []string
+	if strings.HasPrefix(rawValue, "{") && strings.HasSuffix(rawValue, "}") {
+		if op != opEq && op != opNotEq {
+			return condition{}, fmt.Errorf("operator %q does not accept a set", op)
+		}
+		rawValues, err = splitTopLevel(rawValue[1 : len(rawValue)-1])
+		if err != nil {
+			return condition{}, err
+		}
+	} else {
+		rawValues = []string{rawValue}
+	}
+
+	if op == opContains && info.kind != reflect.String {
+		return condition{}, fmt.Errorf("operator %q only applies to text fields, %q is not one", op, field)
+	}
+
+	values := make([]any, len(rawValues))
+	for i, rv := range rawValues {
+		v, err := parseValue(rv, info.kind)
+		if err != nil {
+			return condition{}, fmt.Errorf("invalid value for %q: %w", field, err)
+		}
+		values[i] = v
+	}
+
+	return condition{field: field, info: info, op: op, values: values}, nil
+}
+
+func parseValue(raw string, kind reflect.Kind) (any, error) {
+	if strings.HasPrefix(raw, `"`) {
+		unquoted, err := strconv.Unquote(raw)
+		if err != nil {
+			return nil, fmt.Errorf("malformed quoted value %s", raw)
+		}
+		raw = unquoted
+	}
+
+	switch kind {
+	case reflect.Int, reflect.Int64:
+		return strconv.ParseInt(raw, 10, 64)
+	case reflect.Float64:
+		return strconv.ParseFloat(raw, 64)
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	default:
+		return raw, nil
+	}
+}
+
+// splitTopLevel splits on commas that are outside quotes and braces.
+func splitTopLevel(raw string) ([]string, error) {
+	var parts []string
+	depth, start, quoted := 0, 0, false
+
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case c == '\\' && quoted:
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced braces in %q", raw)
+			}
+		case c == ',' && depth == 0:
+			parts = append(parts, raw[start:i])
+			start = i + 1
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", raw)
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced braces in %q", raw)
+	}
+
+	parts = append(parts, raw[start:])
+	for _, p := range parts {
+		if p == "" {
+			return nil, fmt.Errorf("empty term in %q", raw)
+		}
+	}
+
+	return parts, nil
+}
+
+func parseSort(raw string, schema *modelSchema) ([]sortKey, error) {
+	var keys []sortKey
+	for _, field := range strings.Split(raw, ",") {
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+
+		info, err := schema.scalar(field)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, sortKey{field: field, info: info, desc: desc})
+	}
+
+	return keys, nil
+}
+
+// withIDTiebreak appends an ascending id key unless the sort already uses
+// id, so the order of items is total and pages never overlap.
+func withIDTiebreak(keys []sortKey, schema *modelSchema) []sortKey {
+	info, ok := schema.scalars["id"]
+	if !ok || slices.ContainsFunc(keys, func(k sortKey) bool { return k.field == "id" }) {
+		return keys
+	}
+	return append(keys, sortKey{field: "id", info: info})
+}
+
+// sortSpec renders the sort keys back into their "title,-id" form.
+func (q *listQuery) sortSpec() string {
+	parts := make([]string, len(q.sort))
+	for i, k := range q.sort {
+		parts[i] = k.field
+		if k.desc {
+			parts[i] = "-" + k.field
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseFields(raw string, schema *modelSchema) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		if _, ok := schema.top[field]; !ok {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// applyListQuery filters and sorts items. It returns a new slice and leaves
+// the input untouched.
+func applyListQuery[T any](items []T, q *listQuery) []T {
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		if q.matches(reflect.ValueOf(item)) {
+			out = append(out, item)
+		}
+	}
+
+	slices.SortStableFunc(out, func(a, b T) int {
+		return q.compareKeys(q.key(reflect.ValueOf(a)), q.key(reflect.ValueOf(b)))
+	})
+
+	return out
+}
+
+// project reduces items to the selected fields, or keeps them whole when
+// no fields were selected.
+func project[T any](items []T, q *listQuery) []any {
+	out := make([]any, len(items))
+	for i, item := range items {
+		if len(q.fields) == 0 {
+			out[i] = item
+			continue
+		}
+
+		v := reflect.ValueOf(item)
+		m := make(map[string]any, len(q.fields))
+		for _, field := range q.fields {
+			m[field] = v.Field(q.schema.top[field]).Interface()
+		}
+		out[i] = m
+	}
+
+	return out
+}
+
+// columns lists the scalar fields left after projection, in the order of
+// the selected fields, for tabular formats.
+func (q *listQuery) columns() []string {
+	if len(q.fields) == 0 {
+		return q.schema.paths
+	}
+
+	var columns []string
+	for _, field := range q.fields {
+		for _, path := range q.schema.paths {
+			if path == field || strings.HasPrefix(path, field+".") {
+				columns = append(columns, path)
+			}
+		}
+	}
+	return columns
+}
+
+func (q *listQuery) matches(item reflect.Value) bool {
+	for _, c := range q.filter {
+		v, ok := fieldValue(item, c.info)
+		if !ok || !c.holds(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// key returns the sort key values of an item, nil where a value is missing.
+func (q *listQuery) key(item reflect.Value) []any {
+	key := make([]any, len(q.sort))
+	for i, k := range q.sort {
+		if v, ok := fieldValue(item, k.info); ok {
+			key[i] = v
+		}
+	}
+	return key
+}
+
+func (q *listQuery) compareKeys(a, b []any) int {
+	for i, k := range q.sort {
+		av, bv := a[i], b[i]
+
+		var c int
+		switch {
+		case av == nil && bv == nil:
+		case av == nil:
+			c = 1 // missing values sort last in either direction
+		case bv == nil:
+			c = -1
+		case k.desc:
+			c = compareValues(bv, av)
+		default:
+			c = compareValues(av, bv)
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func (c condition) holds(v any) bool {
+	switch c.op {
+	case opEq:
+		return slices.ContainsFunc(c.values, func(want any) bool { return compareValues(v, want) == 0 })
+	case opNotEq:
+		return !slices.ContainsFunc(c.values, func(want any) bool { return compareValues(v, want) == 0 })
+	case opContains:
+		return strings.Contains(strings.ToLower(v.(string)), strings.ToLower(c.values[0].(string)))
+	}
+
+	r := compareValues(v, c.values[0])
+	switch c.op {
+	case opLess:
+		return r < 0
+	case opLessEq:
+		return r <= 0
+	case opGreater:
+		return r > 0
+	case opGreatEq:
+		return r >= 0
+	}
+	return false
+}
+
+// fieldValue reads a scalar as int64, float64, bool or string. It reports
+// false when a pointer on the way to the field is nil.
+func fieldValue(v reflect.Value, info fieldInfo) (any, bool) {
+	for _, i := range info.index {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int64:
+		return v.Int(), true
+	case reflect.Float64:
+		return v.Float(), true
+	case reflect.Bool:
+		return v.Bool(), true
+	default:
+		return v.String(), true
+	}
+}
+
+func compareValues(a, b any) int {
+	switch av := a.(type) {
+	case int64:
+		return cmp.Compare(av, b.(int64))
+	case float64:
+		return cmp.Compare(av, b.(float64))
+	case bool:
+		bv := b.(bool)
+		switch {
+		case av == bv:
+			return 0
+		case !av:
+			return -1
+		default:
+			return 1
+		}
+	default:
+		return strings.Compare(a.(string), b.(string))
+	}
+}
@@ -0,0 +1,169 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var querySeries = []models.SeriesDetails{
+	{ID: 1, Title: "Major Final", Game: &models.Game{ID: 5, Title: "Counter-Strike 2", Slug: "cs2"}},
+	{ID: 2, Title: "Regional Qualifier", Game: &models.Game{ID: 1, Title: "Dota 2", Slug: "dota2"}},
+	{ID: 3, Title: "Major Semifinal", Game: &models.Game{ID: 5, Title: "Counter-Strike 2", Slug: "cs2"}},
+	{ID: 4, Title: "Showmatch"},
+}
+
+func TestListQuery(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          url.Values
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Filter Contains",
+			query:          url.Values{"filter": {"title~major"}},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data":[
+				{"id":1,"title":"Major Final","game":{"id":5,"title":"Counter-Strike 2","slug":"cs2"}},
+				{"id":3,"title":"Major Semifinal","game":{"id":5,"title":"Counter-Strike 2","slug":"cs2"}}
+			]}`,
+		},
+		{
+			name:           "Filter Nested Set And Range",
+			query:          url.Values{"filter": {"game.slug={cs2,dota2},id>1"}, "fields": {"id"}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":[{"id":2},{"id":3}]}`,
+		},
+		{
+			name:           "Filter Quoted Value",
+			query:          url.Values{"filter": {`title="Major Final"`}, "fields": {"id"}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":[{"id":1}]}`,
+		},
+		{
+			name:           "Sort And Fields",
+			query:          url.Values{"sort": {"title,-id"}, "fields": {"id,title"}},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data":[
+				{"id":1,"title":"Major Final"},
+				{"id":3,"title":"Major Semifinal"},
+				{"id":2,"title":"Regional Qualifier"},
+				{"id":4,"title":"Showmatch"}
+			]}`,
+		},
+		{
+			name:           "Sort Missing Nested Values Last",
+			query:          url.Values{"sort": {"-game.id"}, "fields": {"id"}},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":[{"id":1},{"id":3},{"id":2},{"id":4}]}`,
+		},
+		{
+			name:           "Unknown Filter Field",
+			query:          url.Values{"filter": {"nope=1"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `invalid filter: unknown field "nope"`,
+		},
+		{
+			name:           "Invalid Number",
+			query:          url.Values{"filter": {"id>=ten"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `invalid filter: invalid value for "id": strconv.ParseInt: parsing "ten": invalid syntax`,
+		},
+		{
+			name:           "Contains On Number",
+			query:          url.Values{"filter": {"id~1"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `invalid filter: operator "~" only applies to text fields, "id" is not one`,
+		},
+		{
+			name:           "Unbalanced Set",
+			query:          url.Values{"filter": {"id={1,2"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `invalid filter: unbalanced braces in "id={1,2"`,
+		},
+		{
+			name:           "Unknown Sort Field",
+			query:          url.Values{"sort": {"-rank"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `invalid sort: unknown field "rank"`,
+		},
+		{
+			name:           "Nested Projection Not Allowed",
+			query:          url.Values{"fields": {"game.slug"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `invalid fields: unknown field "game.slug"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			mockService := new(mockLiveService)
+			h := api.NewHandler(context.Background(), mockService)
+
+			mockService.On("GetLiveSeries", mock.Anything, []string(nil)).Return(querySeries, nil).Maybe()
+
+			req := httptest.NewRequest(http.MethodGet, "/series/live?"+tt.query.Encode(), nil)
+			w := httptest.NewRecorder()
+
+			h.GetLiveSeries(w, req)
+
+			result := w.Result()
+			assert.Equal(t, tt.expectedStatus, result.StatusCode)
+
+			if result.StatusCode == http.StatusOK {
+				assert.JSONEq(t, tt.expectedBody, w.Body.String())
+				return
+			}
+
+			assert.Equal(t, "application/problem+json", result.Header.Get("Content-Type"))
+
+			var p struct {
+				Status int    `json:"status"`
+				Detail string `json:"detail"`
+			}
+			assert.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+			assert.Equal(t, tt.expectedStatus, p.Status)
+			assert.Equal(t, tt.expectedBody, p.Detail)
+
+			// invalid expressions are rejected before hitting the service
+			mockService.AssertNotCalled(t, "GetLiveSeries", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestListQueryOnPlayersAndTeams(t *testing.T) {
+	mockService := new(mockLiveService)
+	h := api.NewHandler(context.Background(), mockService)
+
+	mockService.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{
+		{ID: 1, Nickname: "s1mple"},
+		{ID: 2, Nickname: "NiKo"},
+	}, nil)
+	mockService.On("GetLiveTeams", mock.Anything, []string(nil)).Return([]models.Team{
+		{ID: 1, Name: "Team A"},
+		{ID: 2, Name: "Team B"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	h.GetLivePlayers(w, httptest.NewRequest(http.MethodGet, "/players/live?filter=nick_name~niko", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[{"id":2,"nick_name":"NiKo"}]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.GetLiveTeams(w, httptest.NewRequest(http.MethodGet, "/teams/live?filter=id=3", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	h.GetLiveTeams(w, httptest.NewRequest(http.MethodGet, "/teams/live?fields=nick_name", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
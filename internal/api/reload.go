@@ -0,0 +1,108 @@
+package api
+
+import (
+	"slices"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/config"
+	"golang.org/x/time/rate"
+)
+
+// reloadable are the settings Reload applies to a running server, by config
+// file path. The others take a restart.
+var reloadable = map[string]bool{
+	"rate_limit.requests_per_sec":       true,
+	"rate_limit.burst":                  true,
+	"abios.rate_limit.requests_per_sec": true,
+	"abios.rate_limit.burst":            true,
+	"cache.ttl":                         true,
+	"log_level":                         true,
+	"abios.timeout":                     true,
+	"abios.token":                       true,
+	"abios.tokens":                      true,
+	"abios.token_file":                  true,
+	"abios.token_command":               true,
+	"abios.token_command_ttl":           true,
+}
+
+// credentialSettings are the settings the Abios credentials are built from.
+var credentialSettings = []string{
+	"abios.token", "abios.tokens", "abios.token_file", "abios.token_command", "abios.token_command_ttl",
+}
+
+// cacheTTLSetter is implemented by live services whose cache TTL can change.
+type cacheTTLSetter interface {
+	SetCacheTTL(ttl time.Duration)
+}
+
+// Reload applies the settings of cfg that can change while the server runs:
+// the inbound and Abios rate limits, the cache TTL and with it the gRPC
+// watch interval, the log level, the Abios timeout and the Abios
+// credentials. Changed credentials replace the token pool, whose
+// usage counts start over. An invalid cfg is
+// rejected as a whole and the current configuration kept. Every change is
+// logged, those to other settings as waiting for a restart.
+func (s *Server) Reload(cfg *config.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changes := config.Diff(s.cfg, cfg)
+	if len(changes) == 0 {
+		s.logger.Println("config reloaded, nothing changed")
+		return nil
+	}
+	for _, c := range changes {
+		if reloadable[c.Path] {
+			s.logger.Printf("config reloaded: %s", c)
+		} else {
+			s.logger.Printf("config reloaded: %s, takes effect after a restart", c)
+		}
+	}
+
+	s.limiter.SetLimit(rate.Limit(cfg.RateLimitRPS))
+	s.limiter.SetBurst(cfg.RateLimitBurst)
+
+	// an injected client is left alone, like the other Abios settings
+	if s.client != nil {
+		s.client.SetTimeout(cfg.ReqTimeout)
+
+		if slices.ContainsFunc(changes, func(c config.Change) bool { return slices.Contains(credentialSettings, c.Path) }) {
+			s.pool = cfg.TokenPool()
+			if s.pool != nil {
+				s.client.SetTokenPool(s.pool)
+			} else {
+				s.client.SetTokenSource(cfg.TokenSource())
+			}
+		}
+
+		if s.pool != nil {
+			s.pool.SetRateLimit(float64(cfg.AbiosRateLimitRPS), cfg.AbiosRateLimitBurst)
+		}
+		s.client.SetRateLimit(cfg.AbiosRateLimit())
+	}
+
+	if cache, ok := s.liveService.(cacheTTLSetter); ok {
+		cache.SetCacheTTL(cfg.CacheTTL)
+	}
+	s.grpcServer.SetWatchInterval(watchInterval(cfg))
+	s.logLevel.Set(cfg.LogLevel)
+
+	// what runs now, so restart-only changes are reported again next time
+	applied := *s.cfg
+	applied.RateLimitRPS = cfg.RateLimitRPS
+	applied.RateLimitBurst = cfg.RateLimitBurst
+	applied.AbiosRateLimitRPS = cfg.AbiosRateLimitRPS
+	applied.AbiosRateLimitBurst = cfg.AbiosRateLimitBurst
+	applied.CacheTTL = cfg.CacheTTL
+	applied.LogLevel = cfg.LogLevel
+	applied.ReqTimeout = cfg.ReqTimeout
+	applied.Token, applied.Tokens = cfg.Token, cfg.Tokens
+	applied.TokenFile, applied.TokenCommand, applied.TokenTTL = cfg.TokenFile, cfg.TokenCommand, cfg.TokenTTL
+	s.cfg = &applied
+
+	return nil
+}
@@ -0,0 +1,225 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/config"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reloadConfig is a valid configuration against baseURL.
+func reloadConfig(baseURL string) *config.Config {
+	cfg := config.Default()
+	cfg.ApiBaseUrl = baseURL
+	cfg.Token = "token"
+	return cfg
+}
+
+// serve makes n requests to path and returns the last status.
+func serve(srv *api.Server, path string, n int) int {
+	var w *httptest.ResponseRecorder
+	for range n {
+		w = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	return w.Code
+}
+
+func TestReload(t *testing.T) {
+	var logs bytes.Buffer
+	var mu sync.Mutex
+	logger := log.New(writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return logs.Write(p)
+	}), "", 0)
+
+	cfg := reloadConfig("http://127.0.0.1:1")
+	srv, err := api.New(context.Background(), cfg, api.WithLiveService(new(mockLiveService)), api.WithLogger(logger))
+	require.NoError(t, err)
+
+	reloaded := *cfg
+	reloaded.RateLimitRPS = 1
+	reloaded.RateLimitBurst = 2
+	reloaded.SwaggerUI = true
+	require.NoError(t, srv.Reload(&reloaded))
+
+	// a restart-only change is not applied
+	assert.Equal(t, http.StatusNotFound, serve(srv, "/docs", 1))
+
+	assert.Equal(t, http.StatusOK, serve(srv, "/openapi.json", 1))
+	assert.Equal(t, http.StatusTooManyRequests, serve(srv, "/openapi.json", 1))
+
+	require.NoError(t, srv.Reload(&reloaded))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, logs.String(), "config reloaded: rate_limit.requests_per_sec: 5 -> 1\n")
+	assert.Contains(t, logs.String(), "config reloaded: rate_limit.burst: 10 -> 2\n")
+	assert.Equal(t, 2, bytes.Count(logs.Bytes(), []byte("config reloaded: swagger_ui: false -> true, takes effect after a restart\n")),
+		"restart-only changes are reported until the restart")
+	assert.NotContains(t, logs.String(), "nothing changed")
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	cfg := reloadConfig("http://127.0.0.1:1")
+	cfg.RateLimitBurst = 3
+	srv, err := api.New(context.Background(), cfg, api.WithLiveService(new(mockLiveService)))
+	require.NoError(t, err)
+
+	invalid := *cfg
+	invalid.RateLimitRPS = 100
+	invalid.RateLimitBurst = 0
+
+	err = srv.Reload(&invalid)
+	var errs config.Errors
+	require.True(t, errors.As(err, &errs), "want config.Errors, got %v", err)
+
+	// nothing of the invalid config was applied
+	assert.Equal(t, http.StatusOK, serve(srv, "/openapi.json", 3))
+	assert.Equal(t, http.StatusTooManyRequests, serve(srv, "/openapi.json", 1))
+}
+
+func TestReloadAbiosClient(t *testing.T) {
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer upstream.Close()
+
+	cfg := reloadConfig(upstream.URL)
+	cfg.AbiosRateLimitRPS = 1
+	cfg.AbiosRateLimitBurst = 1
+	cfg.CacheTTL = 0
+	srv, err := api.New(context.Background(), cfg)
+	require.NoError(t, err)
+
+	reloaded := *cfg
+	reloaded.AbiosRateLimitRPS = 100
+	reloaded.AbiosRateLimitBurst = 100
+	reloaded.CacheTTL = time.Minute
+	require.NoError(t, srv.Reload(&reloaded))
+
+	// the limiter refills at the new rate from the reload on
+	time.Sleep(50 * time.Millisecond)
+
+	// nothing is live upstream; at the old limits the second upstream call
+	// would have waited a second
+	start := time.Now()
+	assert.Equal(t, http.StatusNotFound, serve(srv, "/series/live", 1))
+	assert.Equal(t, http.StatusNotFound, serve(srv, "/teams/live", 1))
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Greater(t, requests.Load(), int32(1))
+
+	calls := requests.Load()
+	assert.Equal(t, http.StatusNotFound, serve(srv, "/series/live", 1))
+	assert.Equal(t, calls, requests.Load(), "the second series request is cached")
+}
+
+func TestReloadAbiosCredentials(t *testing.T) {
+	var mu sync.Mutex
+	var secrets []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		secrets = append(secrets, r.Header.Get("Abios-Secret"))
+		mu.Unlock()
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer upstream.Close()
+	lastSecret := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return secrets[len(secrets)-1]
+	}
+
+	cfg := reloadConfig(upstream.URL)
+	cfg.CacheTTL = 0
+	srv, err := api.New(context.Background(), cfg)
+	require.NoError(t, err)
+
+	serve(srv, "/series/live", 1)
+	assert.Equal(t, "token", lastSecret())
+
+	rotated := *cfg
+	rotated.Token = "rotated"
+	require.NoError(t, srv.Reload(&rotated))
+	serve(srv, "/series/live", 1)
+	assert.Equal(t, "rotated", lastSecret())
+
+	pooled := rotated
+	pooled.Tokens = []abios.StaticToken{"pooled"}
+	require.NoError(t, srv.Reload(&pooled))
+	serve(srv, "/series/live", 1)
+	assert.Equal(t, "pooled", lastSecret())
+}
+
+func TestReloadAbiosTimeout(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer upstream.Close()
+
+	var logs bytes.Buffer
+	cfg := reloadConfig(upstream.URL)
+	cfg.CacheTTL = 0
+	srv, err := api.New(context.Background(), cfg, api.WithLogger(log.New(&logs, "", 0)))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, serve(srv, "/series/live", 1), "nothing is live")
+
+	reloaded := *cfg
+	reloaded.ReqTimeout = 50 * time.Millisecond
+	require.NoError(t, srv.Reload(&reloaded))
+
+	start := time.Now()
+	assert.Equal(t, http.StatusInternalServerError, serve(srv, "/series/live", 1))
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+	assert.Contains(t, logs.String(), "config reloaded: abios.timeout: 10s -> 50ms\n")
+}
+
+func TestReloadLogLevel(t *testing.T) {
+	// every other upstream request is throttled, which the client logs at
+	// debug level before retrying
+	var requests atomic.Int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1)%2 == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer upstream.Close()
+
+	var logs bytes.Buffer
+	cfg := reloadConfig(upstream.URL)
+	cfg.CacheTTL = 0
+	srv, err := api.New(context.Background(), cfg, api.WithLogger(log.New(&logs, "", 0)))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusNotFound, serve(srv, "/series/live", 1), "nothing is live")
+	assert.NotContains(t, logs.String(), "throttled")
+
+	reloaded := *cfg
+	reloaded.LogLevel = slog.LevelDebug
+	require.NoError(t, srv.Reload(&reloaded))
+
+	assert.Equal(t, http.StatusNotFound, serve(srv, "/series/live", 1), "nothing is live")
+	assert.Contains(t, logs.String(), "config reloaded: log_level: INFO -> DEBUG\n")
+	assert.Contains(t, logs.String(), `level=DEBUG msg="abios: throttled, retrying" path=/series`)
+}
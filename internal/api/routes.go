@@ -0,0 +1,322 @@
+package api
+
+import (
+	"expvar"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+)
+
+// route is one endpoint of the HTTP API. The mux and the OpenAPI document
+// are both built from the route table, which keeps them in sync.
+type route struct {
+	method   string
+	path     string
+	handler  http.Handler
+	describe func(b *specBuilder) *operation
+}
+
+// NewRouter registers every route of the HTTP API, including /openapi.json
+// describing them. The upstream schema drift report is served when drift is
+// set, and the Swagger UI page at /docs when swaggerUI is. Requests are
+// validated against the documented parameters of their route before
+// reaching the handler, and requests matching no route get a problem
+// document.
+func NewRouter(h *handler, graphqlHandler http.Handler, drift *abios.DriftDetector, swaggerUI bool) http.Handler {
+	routes, _ := allRoutes(h, graphqlHandler, drift, swaggerUI)
+	return newMux(routes, nil)
+}
+
+// newMux registers the routes behind request validation. When wrap is set
+// it wraps the handler of every route, which tests use to check responses
+// against the document.
+func newMux(routes []route, wrap func(route, http.Handler) http.Handler) http.Handler {
+	// parameters do not depend on the components, so a scratch builder will do
+	b := &specBuilder{schemas: map[string]*jsonSchema{}}
+
+	mux := http.NewServeMux()
+	for _, rt := range routes {
+		handler := requestValidationMiddleware(rt.handler, rt.describe(b).Parameters)
+
+		if wrap != nil {
+			handler = wrap(rt, handler)
+		}
+
+		mux.Handle(rt.method+" "+rt.path, handler)
+	}
+	return problemMux{mux}
+}
+
+// problemMux answers the requests no route matches, unknown paths and
+// unsupported methods, with a problem document instead of the mux's plain
+// text. Its redirects to cleaned paths pass through.
+type problemMux struct {
+	*http.ServeMux
+}
+
+func (m problemMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, pattern := m.Handler(r)
+	if pattern == "" {
+		// the mux's own handler still sets headers such as Allow
+		h.ServeHTTP(&problemWriter{ResponseWriter: w, r: r}, r)
+		return
+	}
+	m.ServeMux.ServeHTTP(w, r)
+}
+
+// problemWriter replaces the plain text body of a 404 or 405 response with
+// a problem document. Other responses are written as they are.
+type problemWriter struct {
+	http.ResponseWriter
+	r       *http.Request
+	problem bool
+}
+
+func (w *problemWriter) WriteHeader(status int) {
+	if status != http.StatusNotFound && status != http.StatusMethodNotAllowed {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+
+	w.problem = true
+	writeProblem(w.ResponseWriter, w.r, status, "")
+}
+
+func (w *problemWriter) Write(b []byte) (int, error) {
+	if w.problem {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// allRoutes returns the route table, ending with the route serving the
+// OpenAPI document of the table.
+func allRoutes(h *handler, graphqlHandler http.Handler, drift *abios.DriftDetector, swaggerUI bool) ([]route, *openAPIHandler) {
+	routes := apiRoutes(h, graphqlHandler)
+	if drift != nil {
+		routes = append(routes, debugRoutes(drift)...)
+	}
+	if swaggerUI {
+		routes = append(routes, swaggerUIRoutes()...)
+	}
+
+	// the document describes itself too, so it is added last
+	spec := &openAPIHandler{}
+	routes = append(routes, route{
+		method:  http.MethodGet,
+		path:    "/openapi.json",
+		handler: spec,
+		describe: func(b *specBuilder) *operation {
+			return &operation{
+				OperationID: "getOpenAPI",
+				Summary:     "This OpenAPI document",
+				Tags:        []string{"meta"},
+				Responses: map[string]response{
+					"200": {Description: "The OpenAPI 3.1 document.", Content: map[string]mediaType{"application/json": {Schema: &jsonSchema{Type: "object"}}}},
+					"304": {Description: "The document matches If-None-Match."},
+				},
+			}
+		},
+	})
+	spec.routes = routes
+
+	return routes, spec
+}
+
+// swaggerUIRoutes serve the Swagger UI page and the assets it loads.
+func swaggerUIRoutes() []route {
+	assets := make([]any, 0, len(swaggerUIAssets))
+	for _, name := range slices.Sorted(maps.Keys(swaggerUIAssets)) {
+		assets = append(assets, name)
+	}
+
+	return []route{
+		{
+			method:  http.MethodGet,
+			path:    "/docs",
+			handler: http.HandlerFunc(serveSwaggerUI),
+			describe: func(b *specBuilder) *operation {
+				return &operation{
+					OperationID: "getDocs",
+					Summary:     "Swagger UI rendering this document",
+					Tags:        []string{"meta"},
+					Responses: map[string]response{
+						"200": {Description: "The Swagger UI page.", Content: map[string]mediaType{"text/html": {Schema: &jsonSchema{Type: "string"}}}},
+						"304": {Description: "The page matches If-None-Match."},
+					},
+				}
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    "/docs/{asset}",
+			handler: http.HandlerFunc(serveSwaggerUIAsset),
+			describe: func(b *specBuilder) *operation {
+				return &operation{
+					OperationID: "getDocsAsset",
+					Summary:     "A stylesheet or script of the Swagger UI page",
+					Tags:        []string{"meta"},
+					Parameters: []*parameter{
+						{Name: "asset", In: "path", Required: true, Schema: &jsonSchema{Type: "string", Enum: assets}},
+					},
+					Responses: map[string]response{
+						"200": {Description: "The asset.", Content: map[string]mediaType{
+							"text/css":        {Schema: &jsonSchema{Type: "string"}},
+							"text/javascript": {Schema: &jsonSchema{Type: "string"}},
+						}},
+						"304": {Description: "The asset matches If-None-Match."},
+						"400": b.problemResponse("The asset is not part of the page."),
+						"404": b.problemResponse("The asset is missing from the build."),
+					},
+				}
+			},
+		},
+	}
+}
+
+func apiRoutes(h *handler, graphqlHandler http.Handler) []route {
+	return []route{
+		{
+			method:  http.MethodGet,
+			path:    "/series/live",
+			handler: http.HandlerFunc(h.GetLiveSeries),
+			describe: func(b *specBuilder) *operation {
+				return b.listOperation(h.negotiator, "listLiveSeries", "Series currently live",
+					reflect.TypeFor[models.SeriesDetails](), seriesSchema)
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    "/players/live",
+			handler: http.HandlerFunc(h.GetLivePlayers),
+			describe: func(b *specBuilder) *operation {
+				return b.listOperation(h.negotiator, "listLivePlayers", "Players on the rosters of live series",
+					reflect.TypeFor[models.Player](), playerSchema)
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    "/teams/live",
+			handler: http.HandlerFunc(h.GetLiveTeams),
+			describe: func(b *specBuilder) *operation {
+				return b.listOperation(h.negotiator, "listLiveTeams", "Teams of the rosters of live series",
+					reflect.TypeFor[models.Team](), teamSchema)
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    "/graphql",
+			handler: graphqlHandler,
+			describe: func(b *specBuilder) *operation {
+				op := graphqlOperation(b, "queryGraphQLGet")
+				op.Parameters = []*parameter{
+					{Name: "query", In: "query", Required: true, Description: "The GraphQL document.", Schema: &jsonSchema{Type: "string"}},
+					{Name: "operationName", In: "query", Schema: &jsonSchema{Type: "string"}},
+					{Name: "variables", In: "query", Description: "JSON object of variable values.", Schema: &jsonSchema{Type: "string"}},
+				}
+				return op
+			},
+		},
+		{
+			method:  http.MethodPost,
+			path:    "/graphql",
+			handler: graphqlHandler,
+			describe: func(b *specBuilder) *operation {
+				op := graphqlOperation(b, "queryGraphQL")
+				op.RequestBody = &requestBody{
+					Required: true,
+					Content: map[string]mediaType{"application/json": {Schema: &jsonSchema{
+						Type: "object",
+						Properties: map[string]*jsonSchema{
+							"query":         {Type: "string"},
+							"operationName": {Type: "string"},
+							"variables":     {Type: "object"},
+						},
+						Required: []string{"query"},
+					}}},
+				}
+				return op
+			},
+		},
+	}
+}
+
+func debugRoutes(drift *abios.DriftDetector) []route {
+	return []route{
+		{
+			method: http.MethodGet,
+			path:   "/debug/schema-drift",
+			handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeJSON(w, r, http.StatusOK, drift.Report())
+			}),
+			describe: func(b *specBuilder) *operation {
+				return &operation{
+					OperationID: "getSchemaDrift",
+					Summary:     "Fields of Abios responses that differ from the models, per endpoint",
+					Tags:        []string{"debug"},
+					Responses: map[string]response{
+						"200": {
+							Description: "The drift recorded since startup, keyed by Abios endpoint.",
+							Content: map[string]mediaType{"application/json": {Schema: &jsonSchema{
+								Type:                 "object",
+								AdditionalProperties: b.schemaOf(reflect.TypeFor[abios.EndpointDrift]()),
+							}}},
+						},
+						"304": {Description: "The report matches If-None-Match."},
+					},
+				}
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    "/debug/vars",
+			handler: expvar.Handler(),
+			describe: func(b *specBuilder) *operation {
+				return &operation{
+					OperationID: "getMetrics",
+					Summary:     "Runtime metrics, including abios_schema_drift counters",
+					Tags:        []string{"debug"},
+					Responses: map[string]response{
+						"200": {Description: "The published expvar variables.", Content: map[string]mediaType{"application/json": {Schema: &jsonSchema{Type: "object"}}}},
+					},
+				}
+			},
+		},
+	}
+}
+
+func graphqlResultSchema() *jsonSchema {
+	return &jsonSchema{
+		Type: "object",
+		Properties: map[string]*jsonSchema{
+			"data":   {Description: "The query result, null when it failed."},
+			"errors": {Type: "array", Items: &jsonSchema{Type: "object"}},
+		},
+	}
+}
+
+func graphqlOperation(b *specBuilder, id string) *operation {
+	return &operation{
+		OperationID: id,
+		Summary:     "GraphQL query over live series, rosters, teams and players",
+		Tags:        []string{"graphql"},
+		Responses: map[string]response{
+			"200": {
+				Description: "The GraphQL result, errors included.",
+				Content:     map[string]mediaType{"application/json": {Schema: graphqlResultSchema()}},
+			},
+			"400": {
+				Description: "Missing query or malformed request, as a GraphQL result with only errors, or a problem document when the parameters fail validation.",
+				Content: map[string]mediaType{
+					problemContentType: {Schema: b.problemSchema()},
+					"application/json": {Schema: graphqlResultSchema()},
+				},
+			},
+			"429": b.problemResponse("The inbound rate limit is exceeded."),
+		},
+	}
+}
@@ -2,52 +2,247 @@ package api
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"slices"
+	"sync"
+	"time"
 
-	"github.com/benjaminmishra/abios-apis/internal/abios"
 	"github.com/benjaminmishra/abios-apis/internal/config"
+	"github.com/benjaminmishra/abios-apis/internal/graphqlapi"
+	"github.com/benjaminmishra/abios-apis/internal/grpcapi"
 	"github.com/benjaminmishra/abios-apis/internal/service"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
 	"golang.org/x/time/rate"
 )
 
+// clientRateLimit shows the "requests_per_sec" and "burst" the Abios client
+// of the latest server goes out at, which abios.rate_limit.adaptive moves.
+var clientRateLimit = expvar.NewMap("abios_client_rate_limit")
+
 type Server struct {
 	httpServer *http.Server
+	grpcServer *grpcapi.Server
+	listener   net.Listener
+	logger     *log.Logger
+
+	// what Reload changes
+	mu          sync.Mutex
+	logLevel    *slog.LevelVar
+	cfg         *config.Config
+	limiter     *rate.Limiter
+	client      *abios.Client
+	pool        *abios.TokenPool
+	liveService service.LiveService
+}
+
+// Option customises what New builds, for tests and for embedding the
+// server in another process.
+type Option func(o *options)
+
+type options struct {
+	client      abios.AbiosClient
+	liveService service.LiveService
+	listener    net.Listener
+	middleware  []func(http.Handler) http.Handler
+	logger      *log.Logger
+}
+
+// WithAbiosClient uses c instead of a client built from the config. The
+// cassette, strict decoding and drift settings only apply to the built one.
+func WithAbiosClient(c abios.AbiosClient) Option {
+	return func(o *options) {
+		o.client = c
+	}
+}
+
+// WithLiveService serves s as is, instead of the cached service over the
+// Abios client.
+func WithLiveService(s service.LiveService) Option {
+	return func(o *options) {
+		o.liveService = s
+	}
+}
+
+// WithListener makes Start serve HTTP on lis instead of :8080.
+func WithListener(lis net.Listener) Option {
+	return func(o *options) {
+		o.listener = lis
+	}
+}
+
+// WithMiddleware wraps the HTTP handler, outside the inbound rate limiter.
+// The first middleware given is the outermost.
+func WithMiddleware(mw ...func(http.Handler) http.Handler) Option {
+	return func(o *options) {
+		o.middleware = append(o.middleware, mw...)
+	}
+}
+
+// WithLogger sends the server's log lines to l rather than the standard
+// logger.
+func WithLogger(l *log.Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
 }
 
-func New(ctx context.Context, cfg *config.Config) *Server {
+func New(ctx context.Context, cfg *config.Config, opts ...Option) (*Server, error) {
+	o := options{logger: log.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	// the leveled logs, those of the Abios client, share the server's writer
+	logLevel := new(slog.LevelVar)
+	logLevel.Set(cfg.LogLevel)
+	leveled := slog.New(slog.NewTextHandler(o.logger.Writer(), &slog.HandlerOptions{Level: logLevel}))
+
+	client := o.client
+	var built *abios.Client
+	var pool *abios.TokenPool
+	var drift *abios.DriftDetector
+	if client == nil {
+		pool = cfg.TokenPool()
+
+		var err error
+		built, drift, err = newAbiosClient(cfg, pool, o.logger, leveled)
+		if err != nil {
+			return nil, err
+		}
+		client = built
+
+		clientRateLimit.Set("requests_per_sec", expvar.Func(func() any {
+			rps, _ := built.RateLimit()
+			return rps
+		}))
+		clientRateLimit.Set("burst", expvar.Func(func() any {
+			_, burst := built.RateLimit()
+			return burst
+		}))
+	}
 
-	client := abios.NewClient(cfg.ApiBaseUrl, cfg.Token, 10, 5, 10)
-	liveService := service.NewAbiosLiveService(client)
+	liveService := o.liveService
+	if liveService == nil {
+		liveService = service.NewCachedLiveService(service.NewAbiosLiveService(client), cfg.CacheTTL)
+	}
 	handler := NewHandler(ctx, liveService)
 
 	// setup rate limit middleware
-	limiter := rate.NewLimiter(5, 10)
+	limiter := rate.NewLimiter(rate.Limit(cfg.RateLimitRPS), cfg.RateLimitBurst)
 
 	// routes
-	mux := http.NewServeMux()
-	setupRoutes(mux, handler)
+	// the debug routes expose runtime internals, so they are opt in
+	var debugDrift *abios.DriftDetector
+	if cfg.DebugEndpoints {
+		debugDrift = drift
+	}
+	mux := NewRouter(handler, graphqlapi.NewHandler(client, liveService), debugDrift, cfg.SwaggerUI)
+
+	httpHandler := rateLimitMiddleware(mux, limiter)
+	for _, mw := range slices.Backward(o.middleware) {
+		httpHandler = mw(httpHandler)
+	}
 
 	srv := &http.Server{
-		Addr:    ":8080",
-		Handler: rateLimitMiddleware(mux, limiter),
+		Addr:     ":8080",
+		Handler:  httpHandler,
+		ErrorLog: o.logger,
 	}
 
-	return &Server{httpServer: srv}
+	// the gRPC API shares the inbound limiter, so both count against one budget
+	grpcSrv := grpcapi.NewServer(fmt.Sprintf(":%d", cfg.GRPCPort), liveService, limiter, watchInterval(cfg), o.logger)
+
+	return &Server{
+		httpServer:  srv,
+		grpcServer:  grpcSrv,
+		listener:    o.listener,
+		logger:      o.logger,
+		logLevel:    logLevel,
+		cfg:         cfg,
+		limiter:     limiter,
+		client:      built,
+		pool:        pool,
+		liveService: liveService,
+	}, nil
+}
+
+// watchInterval is how often gRPC watches poll: as often as the cache
+// refreshes, but at most once a second.
+func watchInterval(cfg *config.Config) time.Duration {
+	return max(cfg.CacheTTL, time.Second)
 }
 
+func newAbiosClient(cfg *config.Config, pool *abios.TokenPool, logger *log.Logger, leveled *slog.Logger) (*abios.Client, *abios.DriftDetector, error) {
+	drift := abios.NewDriftDetector()
+	if cfg.DriftBaseline != "" {
+		if err := drift.LoadBaseline(cfg.DriftBaseline); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	clientOpts, err := cfg.ClientOptions(pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cfg.CassetteDir != "" {
+		logger.Printf("abios traffic goes through cassette %s in %s mode", cfg.CassetteDir, cfg.CassetteMode)
+	}
+
+	clientOpts = append(clientOpts,
+		abios.WithDriftDetector(drift),
+		abios.WithLogger(leveled),
+	)
+	return abios.NewClient(clientOpts...), drift, nil
+}
+
+// Handler returns the whole HTTP API, middleware included, to mount in
+// another server instead of calling Start.
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
+// Start serves HTTP and gRPC until either fails or is stopped.
 func (s *Server) Start() error {
-	log.Println("server listening on :8080")
-	return s.httpServer.ListenAndServe()
+	httpLis := s.listener
+	if httpLis == nil {
+		var err error
+		if httpLis, err = net.Listen("tcp", s.httpServer.Addr); err != nil {
+			return err
+		}
+	}
+
+	grpcLis, err := net.Listen("tcp", s.grpcServer.Addr())
+	if err != nil {
+		httpLis.Close()
+		return err
+	}
+
+	return s.Serve(httpLis, grpcLis)
 }
 
-func (s *Server) Stop(ctx context.Context) error {
-	log.Println("shutting down server...")
-	return s.httpServer.Shutdown(ctx)
+// Serve is Start on existing listeners, e.g. on ephemeral ports in tests.
+func (s *Server) Serve(httpLis, grpcLis net.Listener) error {
+	errs := make(chan error, 2)
+
+	go func() {
+		errs <- s.grpcServer.Serve(grpcLis)
+	}()
+
+	go func() {
+		s.logger.Printf("server listening on %s", httpLis.Addr())
+		errs <- s.httpServer.Serve(httpLis)
+	}()
+
+	return <-errs
 }
 
-func setupRoutes(mux *http.ServeMux, handler *handler) {
-	mux.HandleFunc("/series/live", handler.GetLiveSeries)
-	mux.HandleFunc("/players/live", handler.GetLivePlayers)
-	mux.HandleFunc("/teams/live", handler.GetLiveTeams)
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Println("shutting down server...")
+	return errors.Join(s.httpServer.Shutdown(ctx), s.grpcServer.Stop(ctx))
 }
@@ -1,28 +1,38 @@
 package api_test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/config"
+	"github.com/benjaminmishra/abios-apis/internal/fakeabios"
 	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/internal/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 type mockLiveService struct {
 	mock.Mock
 }
 
-func (m *mockLiveService) GetLiveSeries(ctx context.Context) ([]models.SeriesDetails, error) {
-	args := m.Called(ctx)
+func (m *mockLiveService) GetLiveSeries(ctx context.Context, games []string) ([]models.SeriesDetails, error) {
+	args := m.Called(ctx, games)
 	return args.Get(0).([]models.SeriesDetails), args.Error(1)
 }
 
-func (m *mockLiveService) GetLivePlayers(ctx context.Context) ([]models.Player, error) {
-	args := m.Called(ctx)
+func (m *mockLiveService) GetLivePlayers(ctx context.Context, games []string) ([]models.Player, error) {
+	args := m.Called(ctx, games)
 
 	if s, ok := args.Get(0).([]models.Player); ok {
 		return s, args.Error(1)
@@ -31,14 +41,15 @@ func (m *mockLiveService) GetLivePlayers(ctx context.Context) ([]models.Player,
 	return nil, args.Error(1)
 }
 
-func (m *mockLiveService) GetLiveTeams(ctx context.Context) ([]models.Team, error) {
-	args := m.Called(ctx)
+func (m *mockLiveService) GetLiveTeams(ctx context.Context, games []string) ([]models.Team, error) {
+	args := m.Called(ctx, games)
 	return args.Get(0).([]models.Team), args.Error(1)
 }
 
 func TestGetLiveSeries(t *testing.T) {
 	tests := []struct {
 		name           string
+		target         string
 		setupMock      func(m *mockLiveService)
 		expectedStatus int
 		expectedBody   string
@@ -46,21 +57,41 @@ func TestGetLiveSeries(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func(m *mockLiveService) {
-				m.On("GetLiveSeries", mock.Anything).Return([]models.SeriesDetails{
+				m.On("GetLiveSeries", mock.Anything, []string(nil)).Return([]models.SeriesDetails{
 					{ID: 1, Title: "Series 1"},
 					{ID: 2, Title: "Series 2"},
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `[{"id":1,"title":"Series 1"},{"id":2,"title":"Series 2"}]`,
+			expectedBody:   `{"data":[{"id":1,"title":"Series 1"},{"id":2,"title":"Series 2"}]}`,
 		},
 		{
 			name: "No Data",
 			setupMock: func(m *mockLiveService) {
-				m.On("GetLiveSeries", mock.Anything).Return([]models.SeriesDetails{}, nil)
+				m.On("GetLiveSeries", mock.Anything, []string(nil)).Return([]models.SeriesDetails{}, nil)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   "No live series found\n",
+			expectedBody:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"No live series found","instance":"/series/live"}`,
+		},
+		{
+			name:   "Game Filter",
+			target: "/series/live?game=cs2,dota2",
+			setupMock: func(m *mockLiveService) {
+				m.On("GetLiveSeries", mock.Anything, []string{"cs2", "dota2"}).Return([]models.SeriesDetails{
+					{ID: 1, Title: "Series 1", Game: &models.Game{ID: 5, Title: "Counter-Strike 2", Slug: "cs2"}},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":[{"id":1,"title":"Series 1","game":{"id":5,"title":"Counter-Strike 2","slug":"cs2"}}]}`,
+		},
+		{
+			name:   "Unknown Game",
+			target: "/series/live?game=chess",
+			setupMock: func(m *mockLiveService) {
+				m.On("GetLiveSeries", mock.Anything, []string{"chess"}).Return([]models.SeriesDetails(nil), fmt.Errorf("%w: %q", service.ErrUnknownGame, "chess"))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"type":"about:blank","title":"Bad Request","status":400,"detail":"unknown game: \"chess\"","instance":"/series/live"}`,
 		},
 	}
 
@@ -72,7 +103,12 @@ func TestGetLiveSeries(t *testing.T) {
 
 			tt.setupMock(mockService)
 
-			req := httptest.NewRequest(http.MethodGet, "/live/series", nil)
+			target := tt.target
+			if target == "" {
+				target = "/series/live"
+			}
+
+			req := httptest.NewRequest(http.MethodGet, target, nil)
 			w := httptest.NewRecorder()
 
 			h.GetLiveSeries(w, req)
@@ -81,10 +117,9 @@ func TestGetLiveSeries(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, result.StatusCode)
 
 			body := w.Body.String()
-			if result.StatusCode == http.StatusOK {
-				assert.JSONEq(t, tt.expectedBody, body)
-			} else {
-				assert.Equal(t, tt.expectedBody, body)
+			assert.JSONEq(t, tt.expectedBody, body)
+			if result.StatusCode != http.StatusOK {
+				assert.Equal(t, "application/problem+json", result.Header.Get("Content-Type"))
 			}
 
 			mockService.AssertExpectations(t)
@@ -95,6 +130,7 @@ func TestGetLiveSeries(t *testing.T) {
 func TestGetLivePlayers(t *testing.T) {
 	tests := []struct {
 		name           string
+		target         string
 		setupMock      func(m *mockLiveService)
 		expectedStatus int
 		expectedBody   string
@@ -102,21 +138,32 @@ func TestGetLivePlayers(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func(m *mockLiveService) {
-				m.On("GetLivePlayers", mock.Anything).Return([]models.Player{
+				m.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{
 					{ID: 1, Nickname: "Player 1"},
 					{ID: 2, Nickname: "Player 2"},
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `[{"id":1,"nick_name":"Player 1"},{"id":2,"nick_name":"Player 2"}]`,
+			expectedBody:   `{"data":[{"id":1,"nick_name":"Player 1"},{"id":2,"nick_name":"Player 2"}]}`,
 		},
 		{
 			name: "No Data",
 			setupMock: func(m *mockLiveService) {
-				m.On("GetLivePlayers", mock.Anything).Return([]models.Player{}, nil)
+				m.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{}, nil)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   "No live players found\n",
+			expectedBody:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"No live players found","instance":"/players/live"}`,
+		},
+		{
+			name:   "Repeated Game Params",
+			target: "/players/live?game=cs2&game=dota2",
+			setupMock: func(m *mockLiveService) {
+				m.On("GetLivePlayers", mock.Anything, []string{"cs2", "dota2"}).Return([]models.Player{
+					{ID: 1, Nickname: "Player 1"},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":[{"id":1,"nick_name":"Player 1"}]}`,
 		},
 	}
 
@@ -128,7 +175,12 @@ func TestGetLivePlayers(t *testing.T) {
 
 			tt.setupMock(mockService)
 
-			req := httptest.NewRequest(http.MethodGet, "/live/players", nil)
+			target := tt.target
+			if target == "" {
+				target = "/players/live"
+			}
+
+			req := httptest.NewRequest(http.MethodGet, target, nil)
 			w := httptest.NewRecorder()
 
 			h.GetLivePlayers(w, req)
@@ -137,10 +189,9 @@ func TestGetLivePlayers(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, result.StatusCode)
 
 			body := w.Body.String()
-			if result.StatusCode == http.StatusOK {
-				assert.JSONEq(t, tt.expectedBody, body)
-			} else {
-				assert.Equal(t, tt.expectedBody, body)
+			assert.JSONEq(t, tt.expectedBody, body)
+			if result.StatusCode != http.StatusOK {
+				assert.Equal(t, "application/problem+json", result.Header.Get("Content-Type"))
 			}
 
 			mockService.AssertExpectations(t)
@@ -151,6 +202,7 @@ func TestGetLivePlayers(t *testing.T) {
 func TestGetLiveTeams(t *testing.T) {
 	tests := []struct {
 		name           string
+		target         string
 		setupMock      func(m *mockLiveService)
 		expectedStatus int
 		expectedBody   string
@@ -158,21 +210,21 @@ func TestGetLiveTeams(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func(m *mockLiveService) {
-				m.On("GetLiveTeams", mock.Anything).Return([]models.Team{
+				m.On("GetLiveTeams", mock.Anything, []string(nil)).Return([]models.Team{
 					{ID: 1, Name: "Team 1"},
 					{ID: 2, Name: "Team 2"},
 				}, nil)
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `[{"id":1,"name":"Team 1"},{"id":2,"name":"Team 2"}]`,
+			expectedBody:   `{"data":[{"id":1,"name":"Team 1"},{"id":2,"name":"Team 2"}]}`,
 		},
 		{
 			name: "No Data",
 			setupMock: func(m *mockLiveService) {
-				m.On("GetLiveTeams", mock.Anything).Return([]models.Team{}, nil)
+				m.On("GetLiveTeams", mock.Anything, []string(nil)).Return([]models.Team{}, nil)
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   "No live teams found\n",
+			expectedBody:   `{"type":"about:blank","title":"Not Found","status":404,"detail":"No live teams found","instance":"/teams/live"}`,
 		},
 	}
 
@@ -184,7 +236,12 @@ func TestGetLiveTeams(t *testing.T) {
 
 			tt.setupMock(mockService)
 
-			req := httptest.NewRequest(http.MethodGet, "/live/teams", nil)
+			target := tt.target
+			if target == "" {
+				target = "/teams/live"
+			}
+
+			req := httptest.NewRequest(http.MethodGet, target, nil)
 			w := httptest.NewRecorder()
 
 			h.GetLiveTeams(w, req)
@@ -193,13 +250,121 @@ func TestGetLiveTeams(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus, result.StatusCode)
 
 			body := w.Body.String()
-			if result.StatusCode == http.StatusOK {
-				assert.JSONEq(t, tt.expectedBody, body)
-			} else {
-				assert.Equal(t, tt.expectedBody, body)
+			assert.JSONEq(t, tt.expectedBody, body)
+			if result.StatusCode != http.StatusOK {
+				assert.Equal(t, "application/problem+json", result.Header.Get("Content-Type"))
 			}
 
 			mockService.AssertExpectations(t)
 		})
 	}
 }
+
+// unreachableConfig points at no Abios at all, so tests fail loudly if the
+// server calls upstream instead of what was injected.
+func unreachableConfig() *config.Config {
+	return &config.Config{ApiBaseUrl: "http://127.0.0.1:1", Token: "token", ReqTimeout: time.Second,
+		AbiosRateLimitRPS: 10, AbiosRateLimitBurst: 10, RateLimitRPS: 10, RateLimitBurst: 10}
+}
+
+func TestNewWithLiveService(t *testing.T) {
+	mockService := new(mockLiveService)
+	mockService.On("GetLiveTeams", mock.Anything, []string{"cs2"}).Return([]models.Team{{ID: 1, Name: "Team 1"}}, nil)
+
+	srv, err := api.New(context.Background(), unreachableConfig(), api.WithLiveService(mockService))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/teams/live?game=cs2", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":[{"id":1,"name":"Team 1"}]}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestNewWithAbiosClient(t *testing.T) {
+	sim := fakeabios.NewSimulator(&fakeabios.Scenario{Initial: fakeabios.DefaultDataset()}, fakeabios.NewManualClock(time.Now()))
+
+	srv, err := api.New(context.Background(), unreachableConfig(), api.WithAbiosClient(sim.Client()))
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/series/live?game=cs2&fields=id", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"data":[{"id":1001}]}`, w.Body.String())
+}
+
+func TestNewWithMiddleware(t *testing.T) {
+	tag := func(name string) func(http.Handler) http.Handler {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Seen-By", name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	srv, err := api.New(context.Background(), unreachableConfig(),
+		api.WithLiveService(new(mockLiveService)),
+		api.WithMiddleware(tag("outer"), tag("inner")),
+	)
+	require.NoError(t, err)
+
+	// the middleware sees responses of the inbound limiter too
+	var w *httptest.ResponseRecorder
+	for range 11 {
+		w = httptest.NewRecorder()
+		srv.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	}
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, []string{"outer", "inner"}, w.Header().Values("X-Seen-By"))
+}
+
+func TestNewWithListenerAndLogger(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	var logs bytes.Buffer
+	var mu sync.Mutex
+	logger := log.New(writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return logs.Write(p)
+	}), "", 0)
+
+	srv, err := api.New(context.Background(), unreachableConfig(),
+		api.WithLiveService(new(mockLiveService)),
+		api.WithListener(lis),
+		api.WithLogger(logger),
+	)
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Start()
+	}()
+
+	resp, err := http.Get("http://" + lis.Addr().String() + "/openapi.json")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, srv.Stop(ctx))
+	assert.ErrorIs(t, <-served, http.ErrServerClosed)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Contains(t, logs.String(), "server listening on "+lis.Addr().String())
+	assert.Contains(t, logs.String(), "shutting down server...")
+	assert.Contains(t, logs.String(), "shutting down grpc server...")
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,26 @@
+#!/bin/sh
+# Vendors the swagger-ui-dist assets served under /docs from the pinned
+# swagger-ui release. go mod download fetches the tagged tree through the
+# module proxy and checks it against the checksum database. Run it with
+# go generate ./internal/api after changing version.
+set -eu
+
+version=5.17.14
+module=github.com/swagger-api/swagger-ui@v$version+incompatible
+
+cd "$(dirname "$0")"
+out=$(pwd)
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+# outside any module, so go.mod and go.sum are left alone
+dir=$(cd "$tmp" && go mod download -json "$module" | sed -n 's/^[[:space:]]*"Dir": "\(.*\)",$/\1/p')
+if [ -z "$dir" ]; then
+	echo "swagger-ui $version: download failed" >&2
+	exit 1
+fi
+
+for f in dist/swagger-ui.css dist/swagger-ui-bundle.js LICENSE NOTICE; do
+	cp "$dir/$f" "$out/$(basename "$f")"
+	chmod 644 "$out/$(basename "$f")"
+done
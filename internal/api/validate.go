@@ -0,0 +1,222 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// violation is one way a request breaks the contract of its route.
+type violation struct {
+	Name   string `json:"name"`
+	In     string `json:"in"`
+	Detail string `json:"detail"`
+}
+
+// requestValidationMiddleware checks the path and query parameters of
+// inbound requests against the documented parameters of the route, and
+// answers 400 listing every violation before the handler is reached.
+func requestValidationMiddleware(next http.Handler, params []*parameter) http.Handler {
+	if len(params) == 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if violations := validateParams(r, params); len(violations) > 0 {
+			writeValidationProblem(w, r, violations)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func validateParams(r *http.Request, params []*parameter) []violation {
+	query := r.URL.Query()
+
+	var violations []violation
+	for _, p := range params {
+		var raw []string
+		switch p.In {
+		case "query":
+			raw = query[p.Name]
+		case "path":
+			if v := r.PathValue(p.Name); v != "" {
+				raw = []string{v}
+			}
+		}
+
+		for _, problem := range p.check(raw) {
+			violations = append(violations, violation{Name: p.Name, In: p.In, Detail: problem})
+		}
+	}
+
+	return violations
+}
+
+// check validates the raw values of the parameter, one per occurrence.
+func (p *parameter) check(raw []string) []string {
+	if len(raw) == 0 {
+		if p.Required {
+			return []string{"is required"}
+		}
+		return nil
+	}
+
+	if p.Schema.Type != "array" {
+		if len(raw) > 1 {
+			return []string{"must be given once"}
+		}
+		return checkParamValue(raw[0], p.Schema)
+	}
+
+	// exploded arrays repeat the parameter, the others separate by commas
+	var values []string
+	for _, v := range raw {
+		if p.Explode != nil && *p.Explode {
+			values = append(values, v)
+		} else {
+			values = append(values, strings.Split(v, ",")...)
+		}
+	}
+
+	var problems []string
+	for _, v := range values {
+		problems = append(problems, checkParamValue(v, p.Schema.Items)...)
+	}
+	return problems
+}
+
+// checkParamValue converts a raw parameter value to the type of its schema
+// and validates it.
+func checkParamValue(raw string, s *jsonSchema) []string {
+	var v any = raw
+
+	switch s.Type {
+	case "integer":
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return []string{fmt.Sprintf("%q is not an integer", raw)}
+		}
+		v = float64(n)
+	case "number":
+		n, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return []string{fmt.Sprintf("%q is not a number", raw)}
+		}
+		v = n
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return []string{fmt.Sprintf("%q is not a boolean", raw)}
+		}
+		v = b
+	}
+
+	return s.check(v, "", nil)
+}
+
+// check validates a decoded JSON value, returning a description of every
+// mismatch prefixed with its location. References are resolved in schemas.
+func (s *jsonSchema) check(v any, at string, schemas map[string]*jsonSchema) []string {
+	if s.Ref != "" {
+		resolved, ok := schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return []string{fmt.Sprintf("%sunresolved reference %s", at, s.Ref)}
+		}
+		return resolved.check(v, at, schemas)
+	}
+
+	if s.Type != "" && !hasJSONType(v, s.Type) {
+		return []string{fmt.Sprintf("%smust be of type %s", at, s.Type)}
+	}
+
+	var problems []string
+
+	if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+		allowed := make([]string, len(s.Enum))
+		for i, e := range s.Enum {
+			allowed[i] = fmt.Sprint(e)
+		}
+		problems = append(problems, fmt.Sprintf("%s%v is not one of %s", at, v, strings.Join(allowed, ", ")))
+	}
+
+	if n, ok := v.(float64); ok {
+		if s.Minimum != nil && n < float64(*s.Minimum) {
+			problems = append(problems, fmt.Sprintf("%smust be at least %d", at, *s.Minimum))
+		}
+		if s.Maximum != nil && n > float64(*s.Maximum) {
+			problems = append(problems, fmt.Sprintf("%smust be at most %d", at, *s.Maximum))
+		}
+	}
+
+	switch v := v.(type) {
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				problems = append(problems, s.Items.check(item, fmt.Sprintf("%s[%d]: ", strings.TrimSuffix(at, ": "), i), schemas)...)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%smissing property %q", at, name))
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			value := v[name]
+			prop, ok := s.Properties[name]
+			switch {
+			case ok:
+			case s.AdditionalProperties != nil:
+				prop = s.AdditionalProperties
+			case s.Properties != nil:
+				problems = append(problems, fmt.Sprintf("%sundocumented property %q", at, name))
+				continue
+			default:
+				continue
+			}
+			problems = append(problems, prop.check(value, fmt.Sprintf("%s.%s: ", strings.TrimSuffix(at, ": "), name), schemas)...)
+		}
+	}
+
+	return problems
+}
+
+func hasJSONType(v any, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == float64(int64(n))
+	case "null":
+		return v == nil
+	}
+	return true
+}
+
+func writeValidationProblem(w http.ResponseWriter, r *http.Request, violations []violation) {
+	details := make([]string, len(violations))
+	for i, v := range violations {
+		details[i] = fmt.Sprintf("%s parameter %q %s", v.In, v.Name, v.Detail)
+	}
+
+	writeProblem(w, r, http.StatusBadRequest, strings.Join(details, "; "), violations...)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+)
+
+// responseValidationMiddleware checks every response of the route against
+// the documented responses of op and reports mismatches. It buffers the
+// whole response.
+func responseValidationMiddleware(next http.Handler, op func() (*operation, map[string]*jsonSchema), report func(error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, r)
+
+		operation, schemas := op()
+		if err := checkResponse(operation, schemas, rec); err != nil {
+			report(fmt.Errorf("%s %s: %w", r.Method, r.URL, err))
+		}
+
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+	})
+}
+
+func checkResponse(op *operation, schemas map[string]*jsonSchema, rec *httptest.ResponseRecorder) error {
+	resp, ok := op.Responses[strconv.Itoa(rec.Code)]
+	if !ok {
+		return fmt.Errorf("undocumented status %d", rec.Code)
+	}
+
+	if rec.Body.Len() == 0 && len(resp.Content) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+	content, ok := resp.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("undocumented content type %q for status %d", mediaType, rec.Code)
+	}
+
+	// only uncompressed JSON bodies can be checked against the schema
+	if rec.Header().Get("Content-Encoding") != "" || content.Schema == nil {
+		return nil
+	}
+
+	var docs [][]byte
+	switch mediaType {
+	case "application/json", problemContentType:
+		docs = [][]byte{rec.Body.Bytes()}
+	case "application/x-ndjson":
+		scanner := bufio.NewScanner(bytes.NewReader(rec.Body.Bytes()))
+		for scanner.Scan() {
+			docs = append(docs, bytes.Clone(scanner.Bytes()))
+		}
+	}
+
+	var problems []string
+	for _, doc := range docs {
+		var v any
+		if err := json.Unmarshal(doc, &v); err != nil {
+			return fmt.Errorf("invalid JSON body: %w", err)
+		}
+		problems = append(problems, content.Schema.check(v, "body: ", schemas)...)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("response %d does not match the contract: %s", rec.Code, strings.Join(problems, "; "))
+	}
+
+	return nil
+}
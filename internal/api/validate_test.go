@@ -0,0 +1,208 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/benjaminmishra/abios-apis/internal/api"
+	"github.com/benjaminmishra/abios-apis/internal/graphqlapi"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/internal/service"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type validationProblem struct {
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	Errors []struct {
+		Name   string `json:"name"`
+		In     string `json:"in"`
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+func TestRequestValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		expectedErrors map[string]string
+	}{
+		{
+			name:           "Limit Not An Integer",
+			target:         "/series/live?limit=abc",
+			expectedErrors: map[string]string{"limit": `"abc" is not an integer`},
+		},
+		{
+			name:           "Limit Out Of Range",
+			target:         "/teams/live?limit=0",
+			expectedErrors: map[string]string{"limit": "must be at least 1"},
+		},
+		{
+			name:           "Unknown Field",
+			target:         "/players/live?fields=id,bogus",
+			expectedErrors: map[string]string{"fields": "bogus is not one of id, nick_name"},
+		},
+		{
+			name:   "Every Violation Is Listed",
+			target: "/series/live?limit=500&fields=title,rosters",
+			expectedErrors: map[string]string{
+				"limit":  "must be at most 200",
+				"fields": "rosters is not one of id, title, game",
+			},
+		},
+		{
+			name:           "Repeated Scalar",
+			target:         "/series/live?sort=id&sort=title",
+			expectedErrors: map[string]string{"sort": "must be given once"},
+		},
+		{
+			name:           "Missing Required Parameter",
+			target:         "/graphql",
+			expectedErrors: map[string]string{"query": "is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mockLiveService)
+			graphqlHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("graphql handler reached")
+			})
+			router := api.NewRouter(api.NewHandler(context.Background(), mockService), graphqlHandler, nil, false)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+			var p validationProblem
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&p))
+			assert.Equal(t, http.StatusBadRequest, p.Status)
+
+			errs := map[string]string{}
+			for _, e := range p.Errors {
+				assert.Equal(t, "query", e.In)
+				errs[e.Name] = e.Detail
+			}
+			assert.Equal(t, tt.expectedErrors, errs)
+
+			mockService.AssertNotCalled(t, "GetLiveSeries", mock.Anything, mock.Anything)
+			mockService.AssertNotCalled(t, "GetLivePlayers", mock.Anything, mock.Anything)
+			mockService.AssertNotCalled(t, "GetLiveTeams", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestValidRequestsPass(t *testing.T) {
+	h := api.NewHandler(context.Background(), liveServiceWithData())
+	router := api.NewRouter(h, http.NotFoundHandler(), nil, false)
+
+	for _, target := range []string{
+		"/series/live?limit=200&fields=id,title",
+		"/series/live?filter=id>0&sort=-title",
+		"/teams/live?fields=name&sort=-name&limit=1",
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusOK, w.Code, target)
+	}
+}
+
+func TestUnmatchedRequestsGetProblems(t *testing.T) {
+	h := api.NewHandler(context.Background(), liveServiceWithData())
+	router := api.NewRouter(h, http.NotFoundHandler(), nil, false)
+
+	tests := []struct {
+		method         string
+		target         string
+		expectedStatus int
+		expectedAllow  string
+	}{
+		{method: http.MethodGet, target: "/matches/live", expectedStatus: http.StatusNotFound},
+		{method: http.MethodDelete, target: "/series/live", expectedStatus: http.StatusMethodNotAllowed, expectedAllow: "GET, HEAD"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+		assert.Equal(t, tt.expectedStatus, w.Code, tt.target)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		assert.Equal(t, tt.expectedAllow, w.Header().Get("Allow"))
+
+		var p struct {
+			Status   int    `json:"status"`
+			Title    string `json:"title"`
+			Instance string `json:"instance"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &p))
+		assert.Equal(t, tt.expectedStatus, p.Status)
+		assert.Equal(t, http.StatusText(tt.expectedStatus), p.Title)
+		assert.Equal(t, tt.target, p.Instance)
+	}
+}
+
+func TestRedirectsPassThrough(t *testing.T) {
+	h := api.NewHandler(context.Background(), liveServiceWithData())
+	router := api.NewRouter(h, http.NotFoundHandler(), nil, false)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/series//live", nil))
+
+	assert.Equal(t, http.StatusTemporaryRedirect, w.Code)
+	assert.Equal(t, "/series/live", w.Header().Get("Location"))
+	assert.NotEqual(t, "application/problem+json", w.Header().Get("Content-Type"))
+}
+
+// Every response the handlers produce must match the documented contract,
+// which the checked router enforces.
+func TestResponsesMatchContract(t *testing.T) {
+	mockService := liveServiceWithData()
+	mockService.On("GetLiveSeries", mock.Anything, []string{"chess"}).Return([]models.SeriesDetails(nil), service.ErrUnknownGame)
+	mockService.On("GetLivePlayers", mock.Anything, []string{"cs2"}).Return(nil, errors.New("upstream down"))
+	mockService.On("GetLiveTeams", mock.Anything, []string{"cs2"}).Return([]models.Team{}, nil)
+
+	h := api.NewHandler(context.Background(), mockService)
+	router := api.NewCheckedRouter(t, h, graphqlapi.NewHandler(abios.NewClient(abios.WithBaseURL("http://127.0.0.1:1")), mockService))
+
+	tests := []struct {
+		target         string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{target: "/series/live", expectedStatus: http.StatusOK},
+		{target: "/players/live", expectedStatus: http.StatusOK},
+		{target: "/teams/live?fields=name", expectedStatus: http.StatusOK},
+		{target: "/series/live", headers: map[string]string{"Accept": "application/x-ndjson"}, expectedStatus: http.StatusOK},
+		{target: "/series/live", headers: map[string]string{"Accept": "text/csv"}, expectedStatus: http.StatusOK},
+		{target: "/series/live", headers: map[string]string{"Accept": "image/png"}, expectedStatus: http.StatusNotAcceptable},
+		{target: "/series/live", headers: map[string]string{"If-None-Match": "*"}, expectedStatus: http.StatusNotModified},
+		{target: "/series/live?filter=bogus=1", expectedStatus: http.StatusBadRequest},
+		{target: "/series/live?limit=x", expectedStatus: http.StatusBadRequest},
+		{target: "/series/live?game=chess", expectedStatus: http.StatusBadRequest},
+		{target: "/teams/live?game=cs2", expectedStatus: http.StatusNotFound},
+		{target: "/players/live?game=cs2", expectedStatus: http.StatusInternalServerError},
+		{target: "/graphql?query={liveTeams{id}}", expectedStatus: http.StatusOK},
+		{target: "/graphql?query={liveTeams{id}}&variables={", expectedStatus: http.StatusBadRequest},
+		{target: "/openapi.json", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, tt.expectedStatus, w.Code, tt.target)
+	}
+}
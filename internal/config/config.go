@@ -1,63 +1,437 @@
 package config
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
 	"os"
-	"strconv"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"gopkg.in/yaml.v3"
 )
 
+const (
+	defaultReqTimeout     = 10 * time.Second
+	defaultRateLimitRPS   = 5
+	defaultRateLimitBurst = 10
+
+	defaultCacheTTL = 5 * time.Second
+	defaultGRPCPort = 9090
+
+	defaultCassetteMode = "replay"
+
+	defaultTokenCommandTTL = 5 * time.Minute
+
+	defaultBulkWaitLimit = 5 * time.Second
+)
+
+// Config is the server's configuration. Load layers it from Default, a
+// config file, ABIOS_* environment variables and command line flags, each
+// overriding the one before.
 type Config struct {
-	ApiBaseUrl     string
-	Token          string
-	ReqTimeout     time.Duration
-	RateLimitRPS   int
-	RateLimitBurst int
+	ApiBaseUrl string
+	// Token is a StaticToken so printing the config never shows it.
+	Token abios.StaticToken
+	// Tokens, when set, are used as a pool, each with the Abios rate limit.
+	Tokens       []abios.StaticToken
+	TokenFile    string
+	TokenCommand []string
+	TokenTTL     time.Duration
+	ReqTimeout   time.Duration
+	// AbiosRateLimitRPS and AbiosRateLimitBurst are the quota of an Abios
+	// credential, RateLimitRPS and RateLimitBurst the inbound limit.
+	AbiosRateLimitRPS   int
+	AbiosRateLimitBurst int
+	RateLimitRPS        int
+	RateLimitBurst      int
+	// BulkWaitLimit sheds bulk Abios requests that would wait longer for
+	// the Abios rate limit.
+	BulkWaitLimit time.Duration
+	// AdaptiveRateLimit lets the upstream rate limit follow Abios' signals.
+	AdaptiveRateLimit bool
+	CacheTTL          time.Duration
+	GRPCPort          int
+	// LogLevel is the least severe level logged.
+	LogLevel  slog.Level
+	SwaggerUI bool
+	// DebugEndpoints serves /debug/schema-drift and /debug/vars, which
+	// expose runtime internals such as the command line.
+	DebugEndpoints bool
+	StrictDecoding bool
+	// DriftBaseline is a cassette directory of recorded Abios responses.
+	DriftBaseline string
+	CassetteDir   string
+	CassetteMode  string
 }
 
-func LoadConfig() (*Config, error) {
-	token := os.Getenv("ABIOS_TOKEN")
-	if token == "" {
-		return nil, fmt.Errorf("ABIOS_TOKEN not set")
+// Default is the configuration of everything left unset, which is all but
+// the Abios token.
+func Default() *Config {
+	return &Config{
+		ApiBaseUrl:          abios.DefaultBaseURL,
+		TokenTTL:            defaultTokenCommandTTL,
+		ReqTimeout:          defaultReqTimeout,
+		AbiosRateLimitRPS:   defaultRateLimitRPS,
+		AbiosRateLimitBurst: defaultRateLimitBurst,
+		RateLimitRPS:        defaultRateLimitRPS,
+		RateLimitBurst:      defaultRateLimitBurst,
+		BulkWaitLimit:       defaultBulkWaitLimit,
+		CacheTTL:            defaultCacheTTL,
+		GRPCPort:            defaultGRPCPort,
+		CassetteMode:        defaultCassetteMode,
 	}
+}
+
+// FieldError is a problem with one setting. Field is where it was set: a
+// path in the config file such as "rate_limit.burst", an environment
+// variable or a flag.
+type FieldError struct {
+	Field string
+	Err   error
+}
 
-	apiBaseUrl := os.Getenv("ABIOS_API_BASE_URL")
-	if apiBaseUrl == "" {
-		return nil, fmt.Errorf("ABIOS_API_BASE_URL not set")
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors is every problem found in a configuration.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
 	}
+	return "invalid configuration: " + strings.Join(msgs, "; ")
+}
 
-	reqTimeoutSecStr := os.Getenv("ABIOS_CLIENT_REQ_TIMEOUT_SEC")
-	if reqTimeoutSecStr == "" {
-		return nil, fmt.Errorf("ABIOS_CLIENT_REQ_TIMEOUT_SEC not set")
+// Flags are the command line overrides of the configuration.
+type Flags struct {
+	file   string
+	values []*flagValue
+}
+
+// BindFlags registers -config, naming a YAML, TOML or JSON config file, and
+// the settings that can be overridden on the command line on fs. Secrets
+// have no flags so they never show up in process listings.
+func BindFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{}
+	fs.StringVar(&f.file, "config", "", "YAML, TOML or JSON config `file`")
+	for _, s := range settings {
+		if s.flag == "" {
+			continue
+		}
+		v := &flagValue{setting: s}
+		fs.Var(v, s.flag, s.usage)
+		f.values = append(f.values, v)
 	}
-	reqTimeoutSec, err := strconv.Atoi(reqTimeoutSecStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid ABIOS_CLIENT_REQ_TIMEOUT_SEC: %v", err)
+	return f
+}
+
+// File is the config file named by -config, if any.
+func (f *Flags) File() string {
+	return f.file
+}
+
+// Load reads the config file, the environment and the flags afresh. Every
+// invalid setting is reported at once, as Errors.
+func (f *Flags) Load() (*Config, error) {
+	cfg := Default()
+	var errs Errors
+
+	if f.file != "" {
+		values, err := readFile(f.file)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, applyFile(cfg, "", values)...)
+	}
+
+	for _, s := range settings {
+		if v := os.Getenv(s.env); v != "" {
+			if err := s.value.set(cfg, v); err != nil {
+				errs = append(errs, &FieldError{Field: s.env, Err: err})
+			}
+		}
+	}
+
+	for _, v := range f.values {
+		if v.isSet {
+			if err := v.setting.value.set(cfg, v.value); err != nil {
+				errs = append(errs, &FieldError{Field: "-" + v.setting.flag, Err: err})
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		errs = append(errs, err.(Errors)...)
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return cfg, nil
+}
+
+// Load parses the command line args, then loads the configuration they
+// describe.
+func Load(args []string) (*Config, error) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	flags := BindFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	return flags.Load()
+}
+
+// Validate checks the configuration, reporting every problem as Errors with
+// the config file path of the setting.
+func (c *Config) Validate() error {
+	var errs Errors
+	check := func(ok bool, field, msg string) {
+		if !ok {
+			errs = append(errs, &FieldError{Field: field, Err: errors.New(msg)})
+		}
+	}
+
+	u, err := url.Parse(c.ApiBaseUrl)
+	check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "abios.base_url", "must be an absolute http or https URL")
+
+	// replayed cassettes are redacted, so offline mode needs no token
+	offline := c.CassetteDir != "" && c.CassetteMode == "replay"
+	hasToken := c.Token != "" || len(c.Tokens) > 0 || c.TokenFile != "" || len(c.TokenCommand) > 0
+	check(hasToken || offline, "abios.token", "must be set, or one of abios.tokens, abios.token_file and abios.token_command")
+
+	check(c.TokenTTL >= 0, "abios.token_command_ttl", "must not be negative")
+	check(c.ReqTimeout >= 0, "abios.timeout", "must not be negative")
+
+	switch c.CassetteMode {
+	case "record", "replay", "replay-or-record":
+	default:
+		check(false, "abios.cassette.mode", `must be "record", "replay" or "replay-or-record"`)
+	}
+
+	check(c.AbiosRateLimitRPS >= 1, "abios.rate_limit.requests_per_sec", "must be at least 1")
+	check(c.AbiosRateLimitBurst >= 1, "abios.rate_limit.burst", "must be at least 1")
+	check(c.BulkWaitLimit >= 0, "abios.rate_limit.bulk_wait_limit", "must not be negative")
+	check(c.RateLimitRPS >= 1, "rate_limit.requests_per_sec", "must be at least 1")
+	check(c.RateLimitBurst >= 1, "rate_limit.burst", "must be at least 1")
+	check(c.CacheTTL >= 0, "cache.ttl", "must not be negative")
+	check(c.GRPCPort >= 1 && c.GRPCPort <= 65535, "grpc.port", "must be between 1 and 65535")
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Change is a setting that differs between two configurations.
+type Change struct {
+	// Path is the setting's path in a config file.
+	Path     string
+	Old, New any
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %v -> %v", c.Path, c.Old, c.New)
+}
+
+// Diff lists the settings that differ from old in new. Secrets print as
+// REDACTED.
+func Diff(old, new *Config) []Change {
+	var changes []Change
+	for _, s := range settings {
+		o, n := s.value.get(old), s.value.get(new)
+		if !reflect.DeepEqual(o, n) {
+			changes = append(changes, Change{Path: s.path, Old: o, New: n})
+		}
+	}
+	return changes
+}
+
+// FileValues returns the configuration as the nested tables of a config
+// file, which Load reads back as the same configuration. Secrets are
+// REDACTED when redacted is set.
+func (c *Config) FileValues(redacted bool) map[string]any {
+	root := map[string]any{}
+	for _, s := range settings {
+		table := root
+		keys := strings.Split(s.path, ".")
+		for _, key := range keys[:len(keys)-1] {
+			if _, ok := table[key]; !ok {
+				table[key] = map[string]any{}
+			}
+			table = table[key].(map[string]any)
+		}
+		table[keys[len(keys)-1]] = fileValue(s.value.get(c), redacted && s.secret)
+	}
+	return root
+}
+
+// fileValue is v as written in a config file.
+func fileValue(v any, redacted bool) any {
+	switch v := v.(type) {
+	case time.Duration:
+		return v.String()
+	case abios.StaticToken:
+		if redacted && v != "" {
+			return v.String()
+		}
+		return string(v)
+	case []abios.StaticToken:
+		tokens := make([]string, len(v))
+		for i, t := range v {
+			tokens[i] = fileValue(t, redacted).(string)
+		}
+		return tokens
+	case []string:
+		return append([]string{}, v...)
+	}
+	return v
+}
+
+// TokenSource prefers a rotatable secret, from a file or a command, over
+// the fixed token. A pool of Tokens is set up separately.
+func (c *Config) TokenSource() abios.TokenSource {
+	switch {
+	case c.TokenFile != "":
+		return abios.NewFileTokenSource(c.TokenFile)
+	case len(c.TokenCommand) > 0:
+		return abios.NewCommandTokenSource(c.TokenTTL, c.TokenCommand[0], c.TokenCommand[1:]...)
+	}
+	return c.Token
+}
+
+// TokenPool returns the pool of Tokens, each token with the configured
+// quota, or nil without them.
+func (c *Config) TokenPool() *abios.TokenPool {
+	if len(c.Tokens) == 0 {
+		return nil
+	}
+
+	pooled := make([]abios.PoolToken, len(c.Tokens))
+	for i, token := range c.Tokens {
+		pooled[i] = abios.PoolToken{Source: token, RequestsPerSec: float64(c.AbiosRateLimitRPS), Burst: c.AbiosRateLimitBurst}
 	}
+	return abios.NewTokenPool(pooled...)
+}
+
+// AbiosRateLimit is the client-wide limit. Each pooled credential has the
+// configured quota, so together they have n times it.
+func (c *Config) AbiosRateLimit() (float64, int) {
+	n := max(len(c.Tokens), 1)
+	return float64(n * c.AbiosRateLimitRPS), n * c.AbiosRateLimitBurst
+}
 
-	rateLimitRPSStr := os.Getenv("ABIOS_CLIENT_RATE_LIMIT_PERSEC")
-	if rateLimitRPSStr == "" {
-		return nil, fmt.Errorf("ABIOS_CLIENT_RATE_LIMIT_PERSEC not set")
+// ClientOptions configure an Abios client from the settings, so that every
+// binary talks to Abios alike. pool, from TokenPool, is used when set; it is
+// passed in for callers that adjust it later.
+func (c *Config) ClientOptions(pool *abios.TokenPool) ([]abios.Option, error) {
+	rps, burst := c.AbiosRateLimit()
+	opts := []abios.Option{
+		abios.WithBaseURL(c.ApiBaseUrl),
+		abios.WithTokenSource(c.TokenSource()),
+		abios.WithTimeout(c.ReqTimeout),
+		abios.WithRateLimit(rps, burst),
+		abios.WithBulkWaitLimit(c.BulkWaitLimit),
+	}
+	if pool != nil {
+		opts = append(opts, abios.WithTokenPool(pool))
+	}
+	if c.AdaptiveRateLimit {
+		opts = append(opts, abios.WithAdaptiveRateLimit(abios.AdaptiveRateLimit{}))
 	}
-	rateLimitRPS, err := strconv.Atoi(rateLimitRPSStr)
+	if c.StrictDecoding {
+		opts = append(opts, abios.WithStrictDecoding())
+	}
+	if c.CassetteDir != "" {
+		cassette, err := abios.NewCassetteTransport(c.CassetteDir, abios.CassetteMode(c.CassetteMode), nil)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, abios.WithTransport(cassette))
+	}
+	return opts, nil
+}
+
+// readFile decodes a config file by its extension.
+func readFile(path string) (map[string]any, error) {
+	b, err := os.ReadFile(path)
 	if err != nil {
-		return nil, fmt.Errorf("invalid ABIOS_CLIENT_RATE_LIMIT_PERSEC: %v", err)
+		return nil, fmt.Errorf("config file: %w", err)
 	}
 
-	rateLimitBurstStr := os.Getenv("ABIOS_CLIENT_RATE_LIMIT_BURST")
-	if rateLimitBurstStr == "" {
-		return nil, fmt.Errorf("ABIOS_CLIENT_RATE_LIMIT_BURST not set")
+	values := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &values)
+	case ".toml":
+		err = toml.Unmarshal(b, &values)
+	case ".json":
+		err = json.Unmarshal(b, &values)
+	default:
+		return nil, fmt.Errorf("config file %s: unknown format %q, want .yaml, .toml or .json", path, ext)
 	}
-	rateLimitBurst, err := strconv.Atoi(rateLimitBurstStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid ABIOS_CLIENT_RATE_LIMIT_BURST: %v", err)
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// applyFile sets the settings in a decoded config file, nested tables
+// making up their paths.
+func applyFile(cfg *Config, prefix string, values map[string]any) Errors {
+	var errs Errors
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		path, v := prefix+key, values[key]
+		if s, ok := settingsByPath[path]; ok {
+			if err := s.value.set(cfg, v); err != nil {
+				errs = append(errs, &FieldError{Field: path, Err: err})
+			}
+			continue
+		}
+		if table, ok := v.(map[string]any); ok {
+			errs = append(errs, applyFile(cfg, path+".", table)...)
+			continue
+		}
+		errs = append(errs, &FieldError{Field: path, Err: errors.New("unknown setting")})
 	}
+	return errs
+}
 
-	return &Config{
-		ApiBaseUrl:     apiBaseUrl,
-		Token:          token,
-		ReqTimeout:     time.Duration(reqTimeoutSec) * time.Second,
-		RateLimitRPS:   rateLimitRPS,
-		RateLimitBurst: rateLimitBurst,
-	}, nil
+// flagValue holds a flag's value until Load applies it over the file and
+// the environment.
+type flagValue struct {
+	setting *setting
+	value   string
+	isSet   bool
+}
+
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return v.value
+}
+
+func (v *flagValue) Set(s string) error {
+	v.value, v.isSet = s, true
+	return nil
+}
+
+func (v *flagValue) IsBoolFlag() bool {
+	return v.setting.isBool
 }
@@ -0,0 +1,316 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/config"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// clearEnv unsets the ABIOS_* variables of the environment the tests run in.
+func clearEnv(t *testing.T) {
+	t.Helper()
+
+	for _, kv := range os.Environ() {
+		if name, _, _ := strings.Cut(kv, "="); strings.HasPrefix(name, "ABIOS_") {
+			t.Setenv(name, "")
+		}
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func fieldErrors(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	var errs config.Errors
+	require.True(t, errors.As(err, &errs), "want config.Errors, got %v", err)
+
+	fields := map[string]string{}
+	for _, e := range errs {
+		fields[e.Field] = e.Err.Error()
+	}
+	return fields
+}
+
+func TestLoadDefaults(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("ABIOS_TOKEN", "secret")
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+
+	want := config.Default()
+	want.Token = "secret"
+	assert.Equal(t, want, cfg)
+	assert.Equal(t, abios.DefaultBaseURL, cfg.ApiBaseUrl)
+	assert.Equal(t, 10*time.Second, cfg.ReqTimeout)
+}
+
+func TestLoadFileFormats(t *testing.T) {
+	files := map[string]string{
+		"config.yaml": `
+abios:
+  token: secret
+  tokens: [a, b]
+  timeout: 3s
+  cassette:
+    dir: ./cassettes
+  rate_limit:
+    requests_per_sec: 2
+    burst: 4
+rate_limit:
+  requests_per_sec: 7
+  burst: 3
+cache:
+  ttl: 1m
+swagger_ui: true
+`,
+		"config.toml": `
+swagger_ui = true
+
+[abios]
+token = "secret"
+tokens = ["a", "b"]
+timeout = "3s"
+
+[abios.cassette]
+dir = "./cassettes"
+
+[abios.rate_limit]
+requests_per_sec = 2
+burst = 4
+
+[rate_limit]
+requests_per_sec = 7
+burst = 3
+
+[cache]
+ttl = "1m"
+`,
+		"config.json": `{
+  "abios": {"token": "secret", "tokens": ["a", "b"], "timeout": 3, "cassette": {"dir": "./cassettes"}, "rate_limit": {"requests_per_sec": 2, "burst": 4}},
+  "rate_limit": {"requests_per_sec": 7, "burst": 3},
+  "cache": {"ttl": "1m"},
+  "swagger_ui": true
+}`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			clearEnv(t)
+
+			cfg, err := config.Load([]string{"-config", writeFile(t, name, content)})
+			require.NoError(t, err)
+
+			assert.Equal(t, abios.StaticToken("secret"), cfg.Token)
+			assert.Equal(t, []abios.StaticToken{"a", "b"}, cfg.Tokens)
+			assert.Equal(t, 3*time.Second, cfg.ReqTimeout)
+			assert.Equal(t, "./cassettes", cfg.CassetteDir)
+			assert.Equal(t, 2, cfg.AbiosRateLimitRPS)
+			assert.Equal(t, 4, cfg.AbiosRateLimitBurst)
+			assert.Equal(t, 7, cfg.RateLimitRPS)
+			assert.Equal(t, 3, cfg.RateLimitBurst)
+			assert.Equal(t, time.Minute, cfg.CacheTTL)
+			assert.True(t, cfg.SwaggerUI)
+			assert.Equal(t, config.Default().GRPCPort, cfg.GRPCPort)
+		})
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+abios:
+  token: from-file
+rate_limit:
+  requests_per_sec: 7
+  burst: 3
+grpc:
+  port: 9000
+`)
+	t.Setenv("ABIOS_CLIENT_RATE_LIMIT_PERSEC", "8")
+	t.Setenv("ABIOS_CLIENT_RATE_LIMIT_BURST", "4")
+
+	cfg, err := config.Load([]string{"-config", path, "-rate-limit", "9", "-swagger-ui", "-log-level", "DEBUG"})
+	require.NoError(t, err)
+
+	assert.Equal(t, abios.StaticToken("from-file"), cfg.Token)
+	assert.Equal(t, 9000, cfg.GRPCPort, "file over defaults")
+	assert.Equal(t, 4, cfg.RateLimitBurst, "environment over file")
+	assert.Equal(t, 9, cfg.RateLimitRPS, "flags over environment")
+	assert.True(t, cfg.SwaggerUI)
+	assert.Equal(t, slog.LevelDebug, cfg.LogLevel)
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "config.yaml", `
+abios:
+  base_url: atlas
+  timeout: soon
+  cassette:
+    mode: rewind
+rate_limit:
+  burst: 0
+grpc:
+  prot: 9000
+log_level: loud
+`)
+	t.Setenv("ABIOS_GRPC_PORT", "70000")
+	t.Setenv("ABIOS_SWAGGER_UI", "maybe")
+
+	_, err := config.Load([]string{"-config", path, "-cache-ttl", "-1s"})
+	require.Error(t, err)
+
+	assert.Equal(t, map[string]string{
+		"abios.timeout":       `want a duration such as "10s" or a number of seconds, got soon`,
+		"grpc.prot":           "unknown setting",
+		"log_level":           "want debug, info, warn or error, got loud",
+		"ABIOS_SWAGGER_UI":    `want true or false, got "maybe"`,
+		"abios.base_url":      "must be an absolute http or https URL",
+		"abios.token":         "must be set, or one of abios.tokens, abios.token_file and abios.token_command",
+		"abios.cassette.mode": `must be "record", "replay" or "replay-or-record"`,
+		"rate_limit.burst":    "must be at least 1",
+		"cache.ttl":           "must not be negative",
+		"grpc.port":           "must be between 1 and 65535",
+	}, fieldErrors(t, err))
+}
+
+func TestLoadOfflineNeedsNoToken(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("ABIOS_CASSETTE_DIR", "./cassettes")
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "replay", cfg.CassetteMode)
+}
+
+func TestLoadBadFile(t *testing.T) {
+	clearEnv(t)
+
+	_, err := config.Load([]string{"-config", writeFile(t, "config.ini", "token = secret")})
+	assert.ErrorContains(t, err, `unknown format ".ini"`)
+
+	_, err = config.Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = config.Load([]string{"-config", writeFile(t, "config.json", "{")})
+	assert.ErrorContains(t, err, "config.json")
+}
+
+// The example config documents every setting with its default.
+func TestExampleConfigIsDefault(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("ABIOS_TOKEN", "secret")
+
+	cfg, err := config.Load([]string{"-config", "../../config.example.yaml"})
+	require.NoError(t, err)
+
+	want := config.Default()
+	want.Token = "secret"
+	assert.Equal(t, want, cfg)
+}
+
+func TestAbiosRateLimit(t *testing.T) {
+	cfg := config.Default()
+	cfg.RateLimitRPS, cfg.RateLimitBurst = 1, 1
+	cfg.AbiosRateLimitRPS, cfg.AbiosRateLimitBurst = 3, 4
+
+	rps, burst := cfg.AbiosRateLimit()
+	assert.Equal(t, 3.0, rps, "the inbound limit does not apply upstream")
+	assert.Equal(t, 4, burst)
+
+	// each pooled token has the quota
+	cfg.Tokens = []abios.StaticToken{"a", "b"}
+	rps, burst = cfg.AbiosRateLimit()
+	assert.Equal(t, 6.0, rps)
+	assert.Equal(t, 8, burst)
+}
+
+func TestClientOptionsShedBulk(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	cfg := config.Default()
+	cfg.ApiBaseUrl = upstream.URL
+	cfg.AbiosRateLimitRPS, cfg.AbiosRateLimitBurst = 1, 1
+	cfg.BulkWaitLimit = 100 * time.Millisecond
+
+	opts, err := cfg.ClientOptions(nil)
+	require.NoError(t, err)
+	client := abios.NewClient(opts...)
+
+	_, err = client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	// the next permit is a second away
+	_, err = client.GetGames(abios.WithPriority(context.Background(), abios.PriorityBulk))
+	assert.ErrorIs(t, err, abios.ErrShed)
+}
+
+func TestDiff(t *testing.T) {
+	old := config.Default()
+	old.Token = "old-secret"
+
+	changed := *old
+	changed.Token = "new-secret"
+	changed.RateLimitBurst = 20
+	changed.CacheTTL = time.Minute
+
+	var diff []string
+	for _, c := range config.Diff(old, &changed) {
+		diff = append(diff, c.String())
+	}
+	assert.Equal(t, []string{
+		"abios.token: REDACTED -> REDACTED",
+		"rate_limit.burst: 10 -> 20",
+		"cache.ttl: 5s -> 1m0s",
+	}, diff)
+
+	assert.Empty(t, config.Diff(old, old))
+}
+
+func TestFileValuesRoundTrip(t *testing.T) {
+	clearEnv(t)
+
+	cfg := config.Default()
+	cfg.Token = "secret"
+	cfg.Tokens = []abios.StaticToken{"a", "b"}
+	cfg.TokenCommand = []string{"vault", "read"}
+	cfg.CacheTTL = 90 * time.Second
+	cfg.SwaggerUI = true
+
+	b, err := yaml.Marshal(cfg.FileValues(false))
+	require.NoError(t, err)
+
+	loaded, err := config.Load([]string{"-config", writeFile(t, "config.yaml", string(b))})
+	require.NoError(t, err)
+	assert.Equal(t, cfg, loaded)
+
+	redacted := cfg.FileValues(true)["abios"].(map[string]any)
+	assert.Equal(t, "REDACTED", redacted["token"])
+	assert.Equal(t, []string{"REDACTED", "REDACTED"}, redacted["tokens"])
+	assert.Equal(t, []string{"vault", "read"}, redacted["token_command"])
+	assert.Equal(t, "1m30s", cfg.FileValues(true)["cache"].(map[string]any)["ttl"])
+}
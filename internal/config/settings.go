@@ -0,0 +1,332 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+)
+
+// setting is one configuration value and the places it can be set.
+type setting struct {
+	// path is where the setting sits in a config file, e.g. "rate_limit.burst".
+	path string
+	env  string
+	// flag is empty for settings not on the command line.
+	flag   string
+	usage  string
+	isBool bool
+	// secret settings print as REDACTED.
+	secret bool
+	value  value
+}
+
+// value reads and writes one field of a Config.
+type value interface {
+	// set parses v, a string from the environment or a flag or a value
+	// decoded from a config file, into the field.
+	set(c *Config, v any) error
+	get(c *Config) any
+}
+
+type field[T any] struct {
+	ptr   func(c *Config) *T
+	parse func(v any) (T, error)
+}
+
+func (f field[T]) set(c *Config, v any) error {
+	t, err := f.parse(v)
+	if err != nil {
+		return err
+	}
+	*f.ptr(c) = t
+	return nil
+}
+
+func (f field[T]) get(c *Config) any {
+	return *f.ptr(c)
+}
+
+var settings = []*setting{
+	{
+		path:  "abios.base_url",
+		env:   "ABIOS_API_BASE_URL",
+		flag:  "base-url",
+		usage: "Abios API base `URL`",
+		value: stringSetting(func(c *Config) *string { return &c.ApiBaseUrl }),
+	},
+	{
+		path:   "abios.token",
+		env:    "ABIOS_TOKEN",
+		secret: true,
+		value:  stringSetting(func(c *Config) *abios.StaticToken { return &c.Token }),
+	},
+	{
+		path:   "abios.tokens",
+		env:    "ABIOS_TOKENS",
+		secret: true,
+		value:  listSetting(func(c *Config) *[]abios.StaticToken { return &c.Tokens }, splitComma),
+	},
+	{
+		path:  "abios.token_file",
+		env:   "ABIOS_TOKEN_FILE",
+		flag:  "token-file",
+		usage: "`file` holding the Abios secret, checked for changes",
+		value: stringSetting(func(c *Config) *string { return &c.TokenFile }),
+	},
+	{
+		path:  "abios.token_command",
+		env:   "ABIOS_TOKEN_COMMAND",
+		value: listSetting(func(c *Config) *[]string { return &c.TokenCommand }, strings.Fields),
+	},
+	{
+		path:  "abios.token_command_ttl",
+		env:   "ABIOS_TOKEN_COMMAND_TTL",
+		value: durationSetting(func(c *Config) *time.Duration { return &c.TokenTTL }),
+	},
+	{
+		path:  "abios.timeout",
+		env:   "ABIOS_CLIENT_REQ_TIMEOUT_SEC",
+		flag:  "timeout",
+		usage: "timeout of Abios requests, retries included, as a `duration`",
+		value: durationSetting(func(c *Config) *time.Duration { return &c.ReqTimeout }),
+	},
+	{
+		path:   "abios.strict_decoding",
+		env:    "ABIOS_STRICT_DECODING",
+		flag:   "strict-decoding",
+		usage:  "fail requests on responses missing or mistyping model fields",
+		isBool: true,
+		value:  boolSetting(func(c *Config) *bool { return &c.StrictDecoding }),
+	},
+	{
+		path:  "abios.drift_baseline",
+		env:   "ABIOS_DRIFT_BASELINE",
+		flag:  "drift-baseline",
+		usage: "cassette `directory` of recorded Abios responses that fields beyond the models are reported against",
+		value: stringSetting(func(c *Config) *string { return &c.DriftBaseline }),
+	},
+	{
+		path:  "abios.cassette.dir",
+		env:   "ABIOS_CASSETTE_DIR",
+		flag:  "cassette-dir",
+		usage: "`directory` Abios traffic is recorded to or replayed from",
+		value: stringSetting(func(c *Config) *string { return &c.CassetteDir }),
+	},
+	{
+		path:  "abios.cassette.mode",
+		env:   "ABIOS_CASSETTE_MODE",
+		flag:  "cassette-mode",
+		usage: "cassette `mode`: record, replay or replay-or-record",
+		value: stringSetting(func(c *Config) *string { return &c.CassetteMode }),
+	},
+	{
+		path:  "abios.rate_limit.requests_per_sec",
+		env:   "ABIOS_UPSTREAM_RATE_LIMIT_PERSEC",
+		flag:  "abios-rate-limit",
+		usage: "`requests` a second to Abios, per Abios token",
+		value: intSetting(func(c *Config) *int { return &c.AbiosRateLimitRPS }),
+	},
+	{
+		path:  "abios.rate_limit.burst",
+		env:   "ABIOS_UPSTREAM_RATE_LIMIT_BURST",
+		flag:  "abios-rate-limit-burst",
+		usage: "`burst` of the Abios rate limit, per Abios token",
+		value: intSetting(func(c *Config) *int { return &c.AbiosRateLimitBurst }),
+	},
+	{
+		path:  "abios.rate_limit.bulk_wait_limit",
+		env:   "ABIOS_UPSTREAM_BULK_WAIT_LIMIT",
+		flag:  "abios-bulk-wait-limit",
+		usage: "longest wait for the Abios rate limit, as a `duration`, before bulk requests are shed; 0 for no limit",
+		value: durationSetting(func(c *Config) *time.Duration { return &c.BulkWaitLimit }),
+	},
+	{
+		path:   "abios.rate_limit.adaptive",
+		env:    "ABIOS_UPSTREAM_RATE_LIMIT_ADAPTIVE",
+		flag:   "abios-rate-limit-adaptive",
+		usage:  "let the Abios rate limit follow upstream signals",
+		isBool: true,
+		value:  boolSetting(func(c *Config) *bool { return &c.AdaptiveRateLimit }),
+	},
+	{
+		path:  "rate_limit.requests_per_sec",
+		env:   "ABIOS_CLIENT_RATE_LIMIT_PERSEC",
+		flag:  "rate-limit",
+		usage: "inbound `requests` a second",
+		value: intSetting(func(c *Config) *int { return &c.RateLimitRPS }),
+	},
+	{
+		path:  "rate_limit.burst",
+		env:   "ABIOS_CLIENT_RATE_LIMIT_BURST",
+		flag:  "rate-limit-burst",
+		usage: "`burst` of the inbound rate limit",
+		value: intSetting(func(c *Config) *int { return &c.RateLimitBurst }),
+	},
+	{
+		path:  "cache.ttl",
+		env:   "ABIOS_CACHE_TTL_SEC",
+		flag:  "cache-ttl",
+		usage: "how long live results are cached, as a `duration`, 0 to disable",
+		value: durationSetting(func(c *Config) *time.Duration { return &c.CacheTTL }),
+	},
+	{
+		path:  "grpc.port",
+		env:   "ABIOS_GRPC_PORT",
+		flag:  "grpc-port",
+		usage: "gRPC `port`",
+		value: intSetting(func(c *Config) *int { return &c.GRPCPort }),
+	},
+	{
+		path:  "log_level",
+		env:   "ABIOS_LOG_LEVEL",
+		flag:  "log-level",
+		usage: "`level` of the logs: debug, info, warn or error",
+		value: levelSetting(func(c *Config) *slog.Level { return &c.LogLevel }),
+	},
+	{
+		path:   "swagger_ui",
+		env:    "ABIOS_SWAGGER_UI",
+		flag:   "swagger-ui",
+		usage:  "serve Swagger UI at /docs",
+		isBool: true,
+		value:  boolSetting(func(c *Config) *bool { return &c.SwaggerUI }),
+	},
+	{
+		path:   "debug_endpoints",
+		env:    "ABIOS_DEBUG_ENDPOINTS",
+		flag:   "debug-endpoints",
+		usage:  "serve the schema drift report and runtime metrics under /debug",
+		isBool: true,
+		value:  boolSetting(func(c *Config) *bool { return &c.DebugEndpoints }),
+	},
+}
+
+var settingsByPath = func() map[string]*setting {
+	m := make(map[string]*setting, len(settings))
+	for _, s := range settings {
+		m[s.path] = s
+	}
+	return m
+}()
+
+func stringSetting[S ~string](ptr func(c *Config) *S) value {
+	return field[S]{ptr: ptr, parse: func(v any) (S, error) {
+		s, ok := v.(string)
+		if !ok {
+			return "", fmt.Errorf("want a string, got %v", v)
+		}
+		return S(s), nil
+	}}
+}
+
+// listSetting takes a list from a config file, or a string split by split
+// from the environment.
+func listSetting[S ~string](ptr func(c *Config) *[]S, split func(string) []string) value {
+	return field[[]S]{ptr: ptr, parse: func(v any) ([]S, error) {
+		var items []string
+		switch v := v.(type) {
+		case string:
+			items = split(v)
+		case []any:
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("want a list of strings, got %v", item)
+				}
+				items = append(items, s)
+			}
+		default:
+			return nil, fmt.Errorf("want a list of strings, got %v", v)
+		}
+
+		var list []S
+		for _, item := range items {
+			list = append(list, S(item))
+		}
+		return list, nil
+	}}
+}
+
+func intSetting(ptr func(c *Config) *int) value {
+	return field[int]{ptr: ptr, parse: toInt}
+}
+
+func boolSetting(ptr func(c *Config) *bool) value {
+	return field[bool]{ptr: ptr, parse: func(v any) (bool, error) {
+		switch v := v.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return false, fmt.Errorf("want true or false, got %q", v)
+			}
+			return b, nil
+		}
+		return false, fmt.Errorf("want true or false, got %v", v)
+	}}
+}
+
+// levelSetting takes a level name such as "warn", in any case.
+func levelSetting(ptr func(c *Config) *slog.Level) value {
+	return field[slog.Level]{ptr: ptr, parse: func(v any) (slog.Level, error) {
+		var level slog.Level
+		s, ok := v.(string)
+		if !ok || level.UnmarshalText([]byte(s)) != nil {
+			return 0, fmt.Errorf("want debug, info, warn or error, got %v", v)
+		}
+		return level, nil
+	}}
+}
+
+// durationSetting takes a duration such as "1m30s", or a whole number of
+// seconds as the _SEC environment variables have always had.
+func durationSetting(ptr func(c *Config) *time.Duration) value {
+	return field[time.Duration]{ptr: ptr, parse: func(v any) (time.Duration, error) {
+		if s, ok := v.(string); ok {
+			if d, err := time.ParseDuration(s); err == nil {
+				return d, nil
+			}
+		}
+
+		secs, err := toInt(v)
+		if err != nil {
+			return 0, fmt.Errorf("want a duration such as \"10s\" or a number of seconds, got %v", v)
+		}
+		return time.Duration(secs) * time.Second, nil
+	}}
+}
+
+// toInt takes the integers of every config file format and the environment.
+func toInt(v any) (int, error) {
+	switch v := v.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32 {
+			return int(v), nil
+		}
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("want a whole number, got %v", v)
+}
+
+func splitComma(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
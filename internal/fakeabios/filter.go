@@ -0,0 +1,171 @@
+package fakeabios
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The Atlas filter syntax, as far as the fake understands it:
+//
+//	filter=lifecycle=live,game.id<={1,5}
+//
+// Conditions are separated by commas outside braces and must all hold. The
+// operators are = != < <= > >= and ~= (case-insensitive substring). A value
+// in braces is a set: <= then means "in" and !<= "not in". Fields are dotted
+// paths into the resource JSON.
+
+type filterOp string
+
+const (
+	opEq       filterOp = "="
+	opNotEq    filterOp = "!="
+	opLess     filterOp = "<"
+	opLessEq   filterOp = "<="
+	opGreater  filterOp = ">"
+	opGreatEq  filterOp = ">="
+	opContains filterOp = "~="
+	opNotIn    filterOp = "!<="
+)
+
+// longest operators first so "<=" is not read as "<"
+var filterOps = []filterOp{opNotIn, opNotEq, opLessEq, opGreatEq, opContains, opEq, opLess, opGreater}
+
+type condition struct {
+	path  []string
+	op    filterOp
+	value string
+	set   []string
+}
+
+func parseFilter(raw string) ([]condition, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var conditions []condition
+	for _, term := range splitTerms(raw) {
+		c, err := parseCondition(term)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+// splitTerms splits on the commas that are not inside braces.
+func splitTerms(raw string) []string {
+	var terms []string
+	depth, start := 0, 0
+	for i, r := range raw {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				terms = append(terms, raw[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(terms, raw[start:])
+}
+
+func parseCondition(term string) (condition, error) {
+	end := strings.IndexAny(term, "=!<>~")
+	if end <= 0 {
+		return condition{}, fmt.Errorf("invalid filter condition %q", term)
+	}
+
+	rest := term[end:]
+	for _, op := range filterOps {
+		if !strings.HasPrefix(rest, string(op)) {
+			continue
+		}
+
+		c := condition{path: strings.Split(term[:end], "."), op: op, value: rest[len(op):]}
+		if strings.HasPrefix(c.value, "{") && strings.HasSuffix(c.value, "}") {
+			if op != opLessEq && op != opNotIn {
+				return condition{}, fmt.Errorf("sets only work with <= and !<=: %q", term)
+			}
+			for _, v := range strings.Split(strings.Trim(c.value, "{}"), ",") {
+				c.set = append(c.set, strings.TrimSpace(v))
+			}
+		} else if op == opNotIn {
+			return condition{}, fmt.Errorf("!<= needs a set: %q", term)
+		}
+		return c, nil
+	}
+
+	return condition{}, fmt.Errorf("invalid filter operator in %q", term)
+}
+
+func (c condition) matches(obj map[string]any) bool {
+	v, ok := lookup(obj, c.path)
+	if !ok {
+		return c.op == opNotEq || c.op == opNotIn
+	}
+
+	if c.set != nil {
+		in := false
+		for _, s := range c.set {
+			if compare(v, s) == 0 {
+				in = true
+				break
+			}
+		}
+		return in == (c.op == opLessEq)
+	}
+
+	switch c.op {
+	case opEq:
+		return compare(v, c.value) == 0
+	case opNotEq:
+		return compare(v, c.value) != 0
+	case opLess:
+		return compare(v, c.value) < 0
+	case opLessEq:
+		return compare(v, c.value) <= 0
+	case opGreater:
+		return compare(v, c.value) > 0
+	case opGreatEq:
+		return compare(v, c.value) >= 0
+	case opContains:
+		return strings.Contains(strings.ToLower(fmt.Sprint(v)), strings.ToLower(c.value))
+	}
+	return false
+}
+
+func lookup(obj map[string]any, path []string) (any, bool) {
+	var v any = obj
+	for _, key := range path {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// compare orders a JSON value against a filter value, numerically for
+// numbers and textually otherwise.
+func compare(v any, s string) int {
+	if n, ok := v.(float64); ok {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			switch {
+			case n < f:
+				return -1
+			case n > f:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(v), s)
+}
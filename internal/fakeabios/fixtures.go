@@ -0,0 +1,49 @@
+package fakeabios
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+)
+
+//go:embed fixtures/*.json
+var defaultFixtures embed.FS
+
+// LoadDataset reads <resource>.json for every resource from fsys, each a
+// JSON array of objects. Missing files leave their resource empty.
+func LoadDataset(fsys fs.FS) (Dataset, error) {
+	ds := Dataset{}
+	for _, resource := range Resources {
+		b, err := fs.ReadFile(fsys, resource+".json")
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var objects []map[string]any
+		if err := json.Unmarshal(b, &objects); err != nil {
+			return nil, fmt.Errorf("fakeabios: %s.json: %w", resource, err)
+		}
+		ds[resource] = objects
+	}
+	return ds, nil
+}
+
+// DefaultDataset is a small built-in dataset of two live series, one
+// upcoming and one finished, with their rosters, teams and players.
+func DefaultDataset() Dataset {
+	sub, err := fs.Sub(defaultFixtures, "fixtures")
+	if err != nil {
+		panic(err)
+	}
+
+	ds, err := LoadDataset(sub)
+	if err != nil {
+		panic(err)
+	}
+	return ds
+}
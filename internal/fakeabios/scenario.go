@@ -0,0 +1,172 @@
+package fakeabios
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is a timeline of upstream changes, such as series going live,
+// rosters swapping players or teams being renamed. Scenario files are YAML
+// or JSON:
+//
+//	name: major final
+//	initial:
+//	  series:
+//	    - {id: 1, title: Final, lifecycle: upcoming, game: {id: 5}, participants: []}
+//	timeline:
+//	  - {at: 10m, resource: series, id: 1, set: {lifecycle: live}}
+//	  - {at: 40m, resource: rosters, id: 11, set: {line_up.players: [{id: 7}]}}
+//	  - {at: 1h, resource: teams, add: {id: 104, name: Newcomers}}
+//	  - {at: 2h, resource: series, id: 1, remove: true}
+type Scenario struct {
+	Name     string  `yaml:"name" json:"name"`
+	Initial  Dataset `yaml:"initial" json:"initial"`
+	Timeline []Event `yaml:"timeline" json:"timeline"`
+}
+
+// Event changes one object of a resource At a time after the scenario
+// starts. Exactly one of Set, Add and Remove is given.
+type Event struct {
+	At       time.Duration `yaml:"at" json:"at"`
+	Resource string        `yaml:"resource" json:"resource"`
+	ID       int           `yaml:"id" json:"id"`
+	// Set assigns fields of the object, dotted paths reaching into nested
+	// objects.
+	Set map[string]any `yaml:"set" json:"set"`
+	// Add appends a new object.
+	Add map[string]any `yaml:"add" json:"add"`
+	// Remove deletes the object.
+	Remove bool `yaml:"remove" json:"remove"`
+}
+
+// LoadScenario reads a YAML or JSON scenario file.
+func LoadScenario(path string) (*Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseScenario(b)
+}
+
+// ParseScenario parses a YAML or JSON scenario and checks its events.
+func ParseScenario(b []byte) (*Scenario, error) {
+	var s Scenario
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("fakeabios: invalid scenario: %w", err)
+	}
+
+	// YAML numbers decode as ints, JSON ones as float64; the server only
+	// deals in the latter
+	if err := normalize(&s.Initial); err != nil {
+		return nil, err
+	}
+	for i := range s.Timeline {
+		e := &s.Timeline[i]
+		if err := normalize(&e.Set); err != nil {
+			return nil, err
+		}
+		if err := normalize(&e.Add); err != nil {
+			return nil, err
+		}
+		if err := e.validate(); err != nil {
+			return nil, fmt.Errorf("fakeabios: timeline event %d: %w", i, err)
+		}
+	}
+
+	// stable, so events at the same time keep their file order
+	slices.SortStableFunc(s.Timeline, func(a, b Event) int {
+		return int(a.At - b.At)
+	})
+
+	return &s, nil
+}
+
+func (e *Event) validate() error {
+	if !slices.Contains(Resources, e.Resource) {
+		return fmt.Errorf("unknown resource %q", e.Resource)
+	}
+	if e.At < 0 {
+		return fmt.Errorf("negative time %s", e.At)
+	}
+
+	actions := 0
+	for _, given := range []bool{e.Set != nil, e.Add != nil, e.Remove} {
+		if given {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return fmt.Errorf("exactly one of set, add and remove is needed")
+	}
+	if e.Add == nil && e.ID == 0 {
+		return fmt.Errorf("set and remove need an id")
+	}
+	return nil
+}
+
+// apply performs the event on ds.
+func (e *Event) apply(ds Dataset) {
+	objects := ds[e.Resource]
+
+	if e.Add != nil {
+		ds[e.Resource] = append(objects, clone(e.Add))
+		return
+	}
+
+	i := slices.IndexFunc(objects, func(obj map[string]any) bool {
+		id, _ := obj["id"].(float64)
+		return int(id) == e.ID
+	})
+	if i < 0 {
+		return
+	}
+
+	if e.Remove {
+		ds[e.Resource] = slices.Delete(objects, i, i+1)
+		return
+	}
+
+	for path, value := range e.Set {
+		setPath(objects[i], strings.Split(path, "."), clone(value))
+	}
+}
+
+func setPath(obj map[string]any, path []string, value any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := obj[key].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			obj[key] = next
+		}
+		obj = next
+	}
+	obj[path[len(path)-1]] = value
+}
+
+func normalize[T any](v *T) error {
+	b, err := json.Marshal(*v)
+	if err != nil {
+		return fmt.Errorf("fakeabios: invalid scenario: %w", err)
+	}
+
+	var out T
+	if err := json.Unmarshal(b, &out); err != nil {
+		return fmt.Errorf("fakeabios: invalid scenario: %w", err)
+	}
+	*v = out
+	return nil
+}
+
+// clone deep copies a decoded JSON value.
+func clone[T any](v T) T {
+	var out T
+	b, _ := json.Marshal(v)
+	_ = json.Unmarshal(b, &out)
+	return out
+}
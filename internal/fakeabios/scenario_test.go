@@ -0,0 +1,133 @@
+package fakeabios_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/fakeabios"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func majorFinal(t *testing.T) (*fakeabios.Simulator, *fakeabios.ManualClock) {
+	t.Helper()
+
+	scenario, err := fakeabios.LoadScenario("scenarios/major_final.yaml")
+	require.NoError(t, err)
+
+	clock := fakeabios.NewManualClock(time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC))
+	return fakeabios.NewSimulator(scenario, clock), clock
+}
+
+func TestSimulatedLifecycle(t *testing.T) {
+	sim, clock := majorFinal(t)
+	client := sim.Client()
+	ctx := context.Background()
+
+	series, err := client.GetLiveSeries(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, series)
+
+	clock.Advance(10 * time.Minute)
+	series, err = client.GetLiveSeries(ctx, []int{5})
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, "Major Grand Final", series[0].Title)
+
+	rosters, err := client.GetRostersByID(ctx, []int{12})
+	require.NoError(t, err)
+	assert.Equal(t, []models.PlayerId{{ID: 1201}, {ID: 1202}}, rosters[0].LineUp.Players)
+
+	clock.Advance(time.Hour)
+	rosters, err = client.GetRostersByID(ctx, []int{12})
+	require.NoError(t, err)
+	assert.Equal(t, []models.PlayerId{{ID: 1201}, {ID: 1203}}, rosters[0].LineUp.Players)
+
+	clock.Advance(30 * time.Minute)
+	teams, err := client.GetTeamsByID(ctx, []int{102})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Team{{ID: 102, Name: "FaZe"}}, teams)
+
+	clock.Advance(time.Hour)
+	series, err = client.GetLiveSeries(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, series)
+}
+
+// The HTTP stand-in serves the same simulation to the real client.
+func TestSimulatedServer(t *testing.T) {
+	sim, clock := majorFinal(t)
+	srv := httptest.NewServer(fakeabios.NewServer(sim, fakeabios.Options{}))
+	defer srv.Close()
+
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100), abios.WithStrictDecoding())
+	ctx := context.Background()
+
+	series, err := client.GetLiveSeries(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, series)
+
+	clock.Advance(time.Hour)
+	series, err = client.GetLiveSeries(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, "live", series[0].Lifecycle)
+
+	players, err := client.GetPlayersByID(ctx, []int{1203})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Player{{ID: 1203, Nickname: "ropz"}}, players)
+}
+
+func TestSimulatorClockTurnedBack(t *testing.T) {
+	sim, clock := majorFinal(t)
+
+	clock.Advance(3 * time.Hour)
+	assert.Equal(t, "over", sim.Dataset()["series"][0]["lifecycle"])
+
+	clock.Advance(-150 * time.Minute)
+	assert.Equal(t, "live", sim.Dataset()["series"][0]["lifecycle"])
+	assert.Equal(t, "FaZe Clan", sim.Dataset()["teams"][1]["name"])
+}
+
+func TestScenarioEvents(t *testing.T) {
+	scenario, err := fakeabios.ParseScenario([]byte(`{
+		"initial": {"teams": [{"id": 1, "name": "One"}, {"id": 2, "name": "Two"}]},
+		"timeline": [
+			{"at": "2m", "resource": "teams", "id": 1, "remove": true},
+			{"at": "1m", "resource": "teams", "add": {"id": 3, "name": "Three"}},
+			{"at": "1m", "resource": "teams", "id": 3, "set": {"name": "Tres", "region.name": "EU"}}
+		]
+	}`))
+	require.NoError(t, err)
+
+	clock := fakeabios.NewManualClock(time.Now())
+	sim := fakeabios.NewSimulator(scenario, clock)
+
+	clock.Advance(time.Minute)
+	teams := sim.Dataset()["teams"]
+	require.Len(t, teams, 3)
+	assert.Equal(t, map[string]any{"id": float64(3), "name": "Tres", "region": map[string]any{"name": "EU"}}, teams[2])
+
+	clock.Advance(time.Minute)
+	assert.Len(t, sim.Dataset()["teams"], 2)
+}
+
+func TestInvalidScenarios(t *testing.T) {
+	for name, body := range map[string]string{
+		"Unknown Resource": `timeline: [{at: 1m, resource: tournaments, id: 1, remove: true}]`,
+		"No Action":        `timeline: [{at: 1m, resource: teams, id: 1}]`,
+		"Two Actions":      `timeline: [{at: 1m, resource: teams, id: 1, remove: true, set: {name: x}}]`,
+		"Missing ID":       `timeline: [{at: 1m, resource: teams, remove: true}]`,
+		"Negative Time":    `timeline: [{at: -1m, resource: teams, id: 1, remove: true}]`,
+		"Bad Duration":     `timeline: [{at: soon, resource: teams, id: 1, remove: true}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := fakeabios.ParseScenario([]byte(body))
+			assert.ErrorContains(t, err, "fakeabios:")
+		})
+	}
+}
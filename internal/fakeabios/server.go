@@ -0,0 +1,253 @@
+// Package fakeabios is a stand-in for the Abios Atlas API, serving fixture
+// data with the Atlas filter and paging syntax, auth checks and injectable
+// faults, for local development and end-to-end tests.
+package fakeabios
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	authHeaderKey = "Abios-Secret"
+
+	defaultTake = 50
+	maxTake     = 50
+)
+
+// Resources are the list endpoints served, each at /<resource>.
+var Resources = []string{"series", "rosters", "teams", "players", "games"}
+
+// Dataset holds the upstream objects by resource name, as raw JSON so fields
+// the wrapper does not model, such as a series lifecycle, can be filtered on.
+type Dataset map[string][]map[string]any
+
+// Source provides the data the server answers from at request time.
+type Source interface {
+	Dataset() Dataset
+}
+
+// Dataset makes a fixed Dataset its own Source.
+func (d Dataset) Dataset() Dataset {
+	return d
+}
+
+// Options configures the behaviour of the fake beyond its data.
+type Options struct {
+	// Token is the Abios-Secret every request must carry. Empty disables
+	// the check.
+	Token string
+	// Latency delays every response, plus up to Jitter more.
+	Latency time.Duration
+	Jitter  time.Duration
+	// ErrorRate is the fraction of requests, 0 to 1, failed with HTTP 500.
+	ErrorRate float64
+	// RequestsPerSec and Burst rate limit requests, answering 429 with
+	// Retry-After beyond it. Zero disables the limit.
+	RequestsPerSec float64
+	Burst          int
+	// RetryAfter is sent with 429 responses, one second when zero.
+	RetryAfter time.Duration
+	// Seed makes error injection and jitter reproducible.
+	Seed uint64
+}
+
+// Server serves a Source the way the Atlas API would.
+type Server struct {
+	source  Source
+	opts    Options
+	limiter *rate.Limiter
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	injected []int
+	requests int
+}
+
+func NewServer(source Source, opts Options) *Server {
+	s := &Server{
+		source: source,
+		opts:   opts,
+		rand:   rand.New(rand.NewPCG(opts.Seed, opts.Seed)),
+	}
+	if opts.RequestsPerSec > 0 {
+		s.limiter = rate.NewLimiter(rate.Limit(opts.RequestsPerSec), max(opts.Burst, 1))
+	}
+	if s.opts.RetryAfter <= 0 {
+		s.opts.RetryAfter = time.Second
+	}
+	return s
+}
+
+// Inject makes the next count requests fail with status, ahead of any other
+// check. 429 responses carry Retry-After.
+func (s *Server) Inject(status, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for range count {
+		s.injected = append(s.injected, status)
+	}
+}
+
+// Requests returns the number of requests served so far.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	status, delay := s.admit()
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status == 0 && s.opts.Token != "" && r.Header.Get(authHeaderKey) != s.opts.Token {
+		status = http.StatusUnauthorized
+	}
+	if status == 0 && s.limiter != nil && !s.limiter.Allow() {
+		status = http.StatusTooManyRequests
+	}
+	if status != 0 {
+		s.writeFault(w, status)
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	resource := strings.Trim(r.URL.Path, "/")
+	if !slices.Contains(Resources, resource) {
+		writeError(w, http.StatusNotFound, "unknown endpoint /"+resource)
+		return
+	}
+
+	query := r.URL.Query()
+
+	filter, err := parseFilter(query.Get("filter"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	skip, take, err := parsePaging(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page(s.source.Dataset()[resource], filter, skip, take))
+}
+
+// admit counts the request and decides up front whether it is failed by
+// injection and how long it is delayed.
+func (s *Server) admit() (status int, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests++
+
+	delay = s.opts.Latency
+	if s.opts.Jitter > 0 {
+		delay += time.Duration(s.rand.Int64N(int64(s.opts.Jitter)))
+	}
+
+	if len(s.injected) > 0 {
+		status, s.injected = s.injected[0], s.injected[1:]
+		return status, delay
+	}
+	if s.opts.ErrorRate > 0 && s.rand.Float64() < s.opts.ErrorRate {
+		return http.StatusInternalServerError, delay
+	}
+	return 0, delay
+}
+
+func (s *Server) writeFault(w http.ResponseWriter, status int) {
+	switch status {
+	case http.StatusTooManyRequests:
+		w.Header().Set("Retry-After", strconv.Itoa(int(max(s.opts.RetryAfter.Round(time.Second), time.Second)/time.Second)))
+		writeError(w, status, "rate limit exceeded")
+	case http.StatusUnauthorized:
+		writeError(w, status, "missing or invalid "+authHeaderKey)
+	default:
+		writeError(w, status, "injected failure")
+	}
+}
+
+func parsePaging(query url.Values) (skip, take int, err error) {
+	take = defaultTake
+	if raw := query.Get("take"); raw != "" {
+		take, err = strconv.Atoi(raw)
+		if err != nil || take < 1 || take > maxTake {
+			return 0, 0, fmt.Errorf("invalid take %q: must be between 1 and %d", raw, maxTake)
+		}
+	}
+
+	if raw := query.Get("skip"); raw != "" {
+		skip, err = strconv.Atoi(raw)
+		if err != nil || skip < 0 {
+			return 0, 0, fmt.Errorf("invalid skip %q", raw)
+		}
+	}
+
+	return skip, take, nil
+}
+
+// page filters the objects and returns the requested page in ID order.
+func page(objects []map[string]any, filter []condition, skip, take int) []map[string]any {
+	out := []map[string]any{}
+	for _, obj := range objects {
+		if matchesAll(obj, filter) {
+			out = append(out, obj)
+		}
+	}
+
+	slices.SortStableFunc(out, func(a, b map[string]any) int {
+		ai, _ := a["id"].(float64)
+		bi, _ := b["id"].(float64)
+		return cmp.Compare(ai, bi)
+	})
+
+	if skip >= len(out) {
+		return []map[string]any{}
+	}
+	return out[skip:min(skip+take, len(out))]
+}
+
+func matchesAll(obj map[string]any, filter []condition) bool {
+	for _, c := range filter {
+		if !c.matches(obj) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"error": map[string]any{"code": status, "message": message}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
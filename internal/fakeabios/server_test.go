@@ -0,0 +1,175 @@
+package fakeabios_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/fakeabios"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func get(t *testing.T, h http.Handler, path string, query url.Values, token string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path+"?"+query.Encode(), nil)
+	if token != "" {
+		req.Header.Set("Abios-Secret", token)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func ids(t *testing.T, w *httptest.ResponseRecorder) []int {
+	t.Helper()
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var objects []struct {
+		ID int `json:"id"`
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&objects))
+
+	out := []int{}
+	for _, o := range objects {
+		out = append(out, o.ID)
+	}
+	return out
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		filter      string
+		expectedIDs []int
+	}{
+		{name: "Live Series", path: "/series", filter: "lifecycle=live", expectedIDs: []int{1001, 1002}},
+		{name: "Live Series Of Games", path: "/series", filter: "lifecycle=live,game.id<={5}", expectedIDs: []int{1001}},
+		{name: "IDs In Set", path: "/rosters", filter: "id<={13,11,99}", expectedIDs: []int{11, 13}},
+		{name: "IDs Not In Set", path: "/teams", filter: "id!<={101,102}", expectedIDs: []int{103, 201, 202}},
+		{name: "Not Equal", path: "/series", filter: "lifecycle!=live", expectedIDs: []int{1003, 1004}},
+		{name: "Range", path: "/players", filter: "id>=1300,id<2102", expectedIDs: []int{1301, 2101}},
+		{name: "Contains", path: "/series", filter: "title~=final", expectedIDs: []int{1001, 1003, 1004}},
+		{name: "No Filter", path: "/games", expectedIDs: []int{1, 5}},
+		{name: "No Match", path: "/teams", filter: "name=Nobody", expectedIDs: []int{}},
+	}
+
+	srv := fakeabios.NewServer(fakeabios.DefaultDataset(), fakeabios.Options{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{}
+			if tt.filter != "" {
+				query.Set("filter", tt.filter)
+			}
+			assert.Equal(t, tt.expectedIDs, ids(t, get(t, srv, tt.path, query, "")))
+		})
+	}
+}
+
+func TestBadRequests(t *testing.T) {
+	srv := fakeabios.NewServer(fakeabios.DefaultDataset(), fakeabios.Options{})
+
+	for _, query := range []url.Values{
+		{"filter": {"id"}},
+		{"filter": {"id={1,2}"}},
+		{"take": {"51"}},
+		{"skip": {"-1"}},
+	} {
+		w := get(t, srv, "/series", query, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code, query.Encode())
+	}
+
+	assert.Equal(t, http.StatusNotFound, get(t, srv, "/tournaments", nil, "").Code)
+}
+
+func TestPaging(t *testing.T) {
+	srv := fakeabios.NewServer(fakeabios.DefaultDataset(), fakeabios.Options{})
+
+	assert.Equal(t, []int{1102, 1201}, ids(t, get(t, srv, "/players", url.Values{"skip": {"1"}, "take": {"2"}}, "")))
+	assert.Equal(t, []int{2201}, ids(t, get(t, srv, "/players", url.Values{"skip": {"7"}, "take": {"2"}}, "")))
+	assert.Equal(t, []int{}, ids(t, get(t, srv, "/players", url.Values{"skip": {"8"}}, "")))
+}
+
+func TestAuth(t *testing.T) {
+	srv := fakeabios.NewServer(fakeabios.DefaultDataset(), fakeabios.Options{Token: "dev"})
+
+	assert.Equal(t, http.StatusUnauthorized, get(t, srv, "/games", nil, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, get(t, srv, "/games", nil, "wrong").Code)
+	assert.Equal(t, http.StatusOK, get(t, srv, "/games", nil, "dev").Code)
+}
+
+func TestFaults(t *testing.T) {
+	t.Run("Rate Limit", func(t *testing.T) {
+		srv := fakeabios.NewServer(fakeabios.DefaultDataset(), fakeabios.Options{RequestsPerSec: 0.001, Burst: 1, RetryAfter: 3 * time.Second})
+
+		assert.Equal(t, http.StatusOK, get(t, srv, "/games", nil, "").Code)
+
+		w := get(t, srv, "/games", nil, "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "3", w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Error Rate", func(t *testing.T) {
+		srv := fakeabios.NewServer(fakeabios.DefaultDataset(), fakeabios.Options{ErrorRate: 1})
+		assert.Equal(t, http.StatusInternalServerError, get(t, srv, "/games", nil, "").Code)
+	})
+
+	t.Run("Injected", func(t *testing.T) {
+		srv := fakeabios.NewServer(fakeabios.DefaultDataset(), fakeabios.Options{})
+		srv.Inject(http.StatusBadGateway, 1)
+		srv.Inject(http.StatusTooManyRequests, 1)
+
+		assert.Equal(t, http.StatusBadGateway, get(t, srv, "/games", nil, "").Code)
+		w := get(t, srv, "/games", nil, "")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Equal(t, http.StatusOK, get(t, srv, "/games", nil, "").Code)
+		assert.Equal(t, 3, srv.Requests())
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		srv := fakeabios.NewServer(fakeabios.DefaultDataset(), fakeabios.Options{Latency: 20 * time.Millisecond})
+
+		start := time.Now()
+		assert.Equal(t, http.StatusOK, get(t, srv, "/games", nil, "").Code)
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+}
+
+// The wrapper's client must work against the fake as is, retries included.
+func TestAbiosClientAgainstFake(t *testing.T) {
+	fake := fakeabios.NewServer(fakeabios.DefaultDataset(), fakeabios.Options{Token: "dev"})
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithToken("dev"), abios.WithRateLimit(100, 100), abios.WithStrictDecoding())
+	ctx := context.Background()
+
+	fake.Inject(http.StatusTooManyRequests, 1)
+
+	series, err := client.GetLiveSeries(ctx, []int{5})
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, "PGL Major Grand Final", series[0].Title)
+	assert.Equal(t, []models.Participant{{Roster: models.RosterId{ID: 11}}, {Roster: models.RosterId{ID: 12}}}, series[0].Participants)
+	assert.Equal(t, 2, fake.Requests())
+
+	rosters, err := client.GetRostersByID(ctx, []int{11, 12})
+	require.NoError(t, err)
+	assert.Len(t, rosters, 2)
+
+	players, err := client.GetPlayersByID(ctx, []int{1101, 1102})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Player{{ID: 1101, Nickname: "s1mple"}, {ID: 1102, Nickname: "b1t"}}, players)
+}
@@ -0,0 +1,168 @@
+package fakeabios
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+)
+
+// Clock tells the simulator what time it is.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock, sped up by a factor of Speed when it is
+// above zero.
+type SystemClock struct {
+	Speed float64
+
+	once  sync.Once
+	start time.Time
+}
+
+func (c *SystemClock) Now() time.Time {
+	now := time.Now()
+	c.once.Do(func() { c.start = now })
+
+	if c.Speed <= 0 {
+		return now
+	}
+	return c.start.Add(time.Duration(float64(now.Sub(c.start)) * c.Speed))
+}
+
+// ManualClock only moves when told to, for tests.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Simulator plays a Scenario against a Clock, starting at the clock's time
+// when created. It is a Source for the Server and, through Client, an
+// in-memory abios.AbiosClient, both seeing the same state.
+type Simulator struct {
+	scenario *Scenario
+	clock    Clock
+	start    time.Time
+
+	mu      sync.Mutex
+	state   Dataset
+	applied int
+}
+
+func NewSimulator(scenario *Scenario, clock Clock) *Simulator {
+	return &Simulator{
+		scenario: scenario,
+		clock:    clock,
+		start:    clock.Now(),
+		state:    clone(scenario.Initial),
+	}
+}
+
+// Elapsed returns the scenario time.
+func (s *Simulator) Elapsed() time.Duration {
+	return s.clock.Now().Sub(s.start)
+}
+
+// Dataset returns a copy of the state at the current scenario time.
+func (s *Simulator) Dataset() Dataset {
+	elapsed := s.Elapsed()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// a clock turned back replays the timeline from the start
+	if s.applied > 0 && s.scenario.Timeline[s.applied-1].At > elapsed {
+		s.state, s.applied = clone(s.scenario.Initial), 0
+	}
+
+	for s.applied < len(s.scenario.Timeline) && s.scenario.Timeline[s.applied].At <= elapsed {
+		s.scenario.Timeline[s.applied].apply(s.state)
+		s.applied++
+	}
+
+	if s.state == nil {
+		return Dataset{}
+	}
+	return clone(s.state)
+}
+
+// Client returns an abios.AbiosClient answering from the simulation the way
+// the Atlas API would, without going through HTTP.
+func (s *Simulator) Client() abios.AbiosClient {
+	return &simulatedClient{source: s}
+}
+
+type simulatedClient struct {
+	source Source
+}
+
+func (c *simulatedClient) GetLiveSeries(ctx context.Context, gameIDs []int) ([]models.Series, error) {
+	filter := []condition{{path: []string{"lifecycle"}, op: opEq, value: "live"}}
+	if len(gameIDs) > 0 {
+		filter = append(filter, idsIn("game.id", gameIDs))
+	}
+	return query[models.Series](c.source, "series", filter)
+}
+
+func (c *simulatedClient) GetGames(ctx context.Context) ([]models.Game, error) {
+	return query[models.Game](c.source, "games", nil)
+}
+
+func (c *simulatedClient) GetRostersByID(ctx context.Context, rosterIDs []int) ([]models.Roster, error) {
+	return query[models.Roster](c.source, "rosters", []condition{idsIn("id", rosterIDs)})
+}
+
+func (c *simulatedClient) GetTeamsByID(ctx context.Context, teamIDs []int) ([]models.Team, error) {
+	return query[models.Team](c.source, "teams", []condition{idsIn("id", teamIDs)})
+}
+
+func (c *simulatedClient) GetPlayersByID(ctx context.Context, playerIDs []int) ([]models.Player, error) {
+	return query[models.Player](c.source, "players", []condition{idsIn("id", playerIDs)})
+}
+
+func idsIn(path string, ids []int) condition {
+	set := make([]string, len(ids))
+	for i, id := range ids {
+		set[i] = strconv.Itoa(id)
+	}
+	return condition{path: strings.Split(path, "."), op: opLessEq, set: set}
+}
+
+// query answers like a single unpaged request to the Server would.
+func query[T any](source Source, resource string, filter []condition) ([]T, error) {
+	b, err := json.Marshal(page(source.Dataset()[resource], filter, 0, defaultTake))
+	if err != nil {
+		return nil, err
+	}
+
+	var out []T
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,147 @@
+// Package graphqlapi serves live series, rosters, teams and players as one
+// GraphQL graph.
+package graphqlapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/benjaminmishra/abios-apis/internal/service"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+)
+
+const (
+	// maxDepth bounds field nesting. Data queries cannot go past 4
+	// (liveSeries.rosters.team.name), so it is there for introspection,
+	// whose types.fields.type cycle is endless. The introspection query of
+	// GraphiQL and codegen tools needs 13, more for tools unwrapping deeper
+	// list and non-null types.
+	maxDepth = 15
+	// maxComplexity bounds the estimated number of resolved fields per query
+	maxComplexity = 5000
+	// listSizeEstimate is the assumed length of every list when estimating
+	listSizeEstimate = 10
+)
+
+// nested fields returning lists, which multiply the cost of their children
+var listFields = map[string]bool{
+	"rosters": true,
+	"players": true,
+}
+
+type handler struct {
+	schema *graphql.Schema
+	client abios.AbiosClient
+}
+
+// NewHandler serves GraphQL queries over GET and POST. Nested rosters,
+// teams and players are batched per request onto single calls to the Abios
+// client.
+func NewHandler(client abios.AbiosClient, liveService service.LiveService) http.Handler {
+	schema := graphql.MustParseSchema(schemaSDL,
+		&rootResolver{liveService: liveService},
+		graphql.MaxDepth(maxDepth),
+	)
+
+	return &handler{schema: schema, client: client}
+}
+
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if vars := r.URL.Query().Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				writeError(w, http.StatusBadRequest, "invalid variables: "+err.Error())
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if strings.TrimSpace(req.Query) == "" {
+		writeError(w, http.StatusBadRequest, "missing query")
+		return
+	}
+
+	ctx := withLoaders(r.Context(), newLoaders(h.client))
+	ctx = withComplexityBudget(ctx, maxComplexity)
+
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// writeError answers a request that never reached execution with a GraphQL
+// response holding just the error, as GraphQL over HTTP asks.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&graphql.Response{Errors: []*gqlerrors.QueryError{{Message: msg}}})
+}
+
+type budgetKey struct{}
+
+func withComplexityBudget(ctx context.Context, budget int64) context.Context {
+	remaining := &atomic.Int64{}
+	remaining.Store(budget)
+	return context.WithValue(ctx, budgetKey{}, remaining)
+}
+
+// chargeComplexity estimates the cost of the root field being resolved from
+// its selections and takes it from the request budget, failing once the
+// budget is spent. Charging per root field keeps aliased copies of a field
+// from multiplying the work past the limit.
+func chargeComplexity(ctx context.Context, rootIsList bool) error {
+	remaining, ok := ctx.Value(budgetKey{}).(*atomic.Int64)
+	if !ok {
+		return nil
+	}
+
+	rootFactor := int64(1)
+	if rootIsList {
+		rootFactor = listSizeEstimate
+	}
+
+	cost := rootFactor
+	for _, path := range graphql.SelectedFieldNames(ctx) {
+		segments := strings.Split(path, ".")
+
+		factor := rootFactor
+		for _, parent := range segments[:len(segments)-1] {
+			if listFields[parent] {
+				factor *= listSizeEstimate
+			}
+		}
+		cost += factor
+	}
+
+	if remaining.Add(-cost) < 0 {
+		return fmt.Errorf("query too complex: estimated cost exceeds the limit of %d", maxComplexity)
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+package graphqlapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/benjaminmishra/abios-apis/internal/graphqlapi"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type mockAbiosClient struct {
+	mock.Mock
+}
+
+func (m *mockAbiosClient) GetLiveSeries(ctx context.Context, gameIDs []int) ([]models.Series, error) {
+	args := m.Called(ctx, gameIDs)
+	return args.Get(0).([]models.Series), args.Error(1)
+}
+
+func (m *mockAbiosClient) GetGames(ctx context.Context) ([]models.Game, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Game), args.Error(1)
+}
+
+func (m *mockAbiosClient) GetRostersByID(ctx context.Context, ids []int) ([]models.Roster, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]models.Roster), args.Error(1)
+}
+
+func (m *mockAbiosClient) GetPlayersByID(ctx context.Context, ids []int) ([]models.Player, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]models.Player), args.Error(1)
+}
+
+func (m *mockAbiosClient) GetTeamsByID(ctx context.Context, ids []int) ([]models.Team, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]models.Team), args.Error(1)
+}
+
+type mockLiveService struct {
+	mock.Mock
+}
+
+func (m *mockLiveService) GetLiveSeries(ctx context.Context, games []string) ([]models.SeriesDetails, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).([]models.SeriesDetails), args.Error(1)
+}
+
+func (m *mockLiveService) GetLivePlayers(ctx context.Context, games []string) ([]models.Player, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).([]models.Player), args.Error(1)
+}
+
+func (m *mockLiveService) GetLiveTeams(ctx context.Context, games []string) ([]models.Team, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).([]models.Team), args.Error(1)
+}
+
+var cs2 = models.Game{ID: 5, Title: "Counter-Strike 2", Slug: "cs2"}
+
+type gqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func post(t *testing.T, h http.Handler, query string) gqlResponse {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]any{"query": query})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body))))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp gqlResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	return resp
+}
+
+func TestNestedQueryIsBatched(t *testing.T) {
+	client := new(mockAbiosClient)
+	live := new(mockLiveService)
+	h := graphqlapi.NewHandler(client, live)
+
+	live.On("GetLiveSeries", mock.Anything, []string{"cs2"}).Return([]models.SeriesDetails{
+		{ID: 1, Title: "Series 1", Game: &cs2, RosterIDs: []int{20, 10}},
+		{ID: 2, Title: "Series 2", Game: &cs2, RosterIDs: []int{30, 10}},
+	}, nil).Once()
+
+	// one call per resource type, whatever the number of series and rosters
+	client.On("GetRostersByID", mock.Anything, []int{10, 20, 30}).Return([]models.Roster{
+		{ID: 10, TeamId: models.TeamId{ID: 100}, LineUp: models.LineUp{Players: []models.PlayerId{{ID: 1}, {ID: 2}}}},
+		{ID: 20, TeamId: models.TeamId{ID: 200}, LineUp: models.LineUp{Players: []models.PlayerId{{ID: 3}}}},
+		{ID: 30, TeamId: models.TeamId{ID: 300}, LineUp: models.LineUp{Players: []models.PlayerId{{ID: 4}}}},
+	}, nil).Once()
+	client.On("GetTeamsByID", mock.Anything, []int{100, 200, 300}).Return([]models.Team{
+		{ID: 100, Name: "Team A"}, {ID: 200, Name: "Team B"}, {ID: 300, Name: "Team C"},
+	}, nil).Once()
+	client.On("GetPlayersByID", mock.Anything, []int{1, 2, 3, 4}).Return([]models.Player{
+		{ID: 1, Nickname: "p1"}, {ID: 2, Nickname: "p2"}, {ID: 3, Nickname: "p3"}, {ID: 4, Nickname: "p4"},
+	}, nil).Once()
+
+	resp := post(t, h, `{
+		liveSeries(games: ["cs2"]) {
+			id
+			title
+			game { slug }
+			rosters {
+				id
+				team { name }
+				players { id nickName }
+			}
+		}
+	}`)
+
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"liveSeries": [
+		{"id": "1", "title": "Series 1", "game": {"slug": "cs2"}, "rosters": [
+			{"id": "20", "team": {"name": "Team B"}, "players": [{"id": "3", "nickName": "p3"}]},
+			{"id": "10", "team": {"name": "Team A"}, "players": [{"id": "1", "nickName": "p1"}, {"id": "2", "nickName": "p2"}]}
+		]},
+		{"id": "2", "title": "Series 2", "game": {"slug": "cs2"}, "rosters": [
+			{"id": "30", "team": {"name": "Team C"}, "players": [{"id": "4", "nickName": "p4"}]},
+			{"id": "10", "team": {"name": "Team A"}, "players": [{"id": "1", "nickName": "p1"}, {"id": "2", "nickName": "p2"}]}
+		]}
+	]}`, string(resp.Data))
+
+	client.AssertExpectations(t)
+	live.AssertExpectations(t)
+}
+
+func TestUnselectedResourcesAreNotFetched(t *testing.T) {
+	client := new(mockAbiosClient)
+	live := new(mockLiveService)
+	h := graphqlapi.NewHandler(client, live)
+
+	live.On("GetLiveSeries", mock.Anything, []string(nil)).Return([]models.SeriesDetails{
+		{ID: 1, RosterIDs: []int{10}},
+	}, nil)
+	client.On("GetRostersByID", mock.Anything, []int{10}).Return([]models.Roster{
+		{ID: 10, TeamId: models.TeamId{ID: 100}, LineUp: models.LineUp{Players: []models.PlayerId{{ID: 1}}}},
+	}, nil)
+	client.On("GetTeamsByID", mock.Anything, []int{100}).Return([]models.Team{{ID: 100, Name: "Team A"}}, nil)
+
+	resp := post(t, h, `{ liveSeries { id rosters { team { name } } } }`)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"liveSeries": [{"id": "1", "rosters": [{"team": {"name": "Team A"}}]}]}`, string(resp.Data))
+
+	client.AssertNotCalled(t, "GetPlayersByID", mock.Anything, mock.Anything)
+}
+
+func TestLivePlayersAndTeams(t *testing.T) {
+	live := new(mockLiveService)
+	h := graphqlapi.NewHandler(new(mockAbiosClient), live)
+
+	live.On("GetLivePlayers", mock.Anything, []string{"dota2"}).Return([]models.Player{{ID: 1, Nickname: "p1"}}, nil)
+	live.On("GetLiveTeams", mock.Anything, []string(nil)).Return([]models.Team{{ID: 100, Name: "Team A"}}, nil)
+
+	resp := post(t, h, `{ livePlayers(games: ["dota2"]) { nickName } liveTeams { id name } }`)
+	require.Empty(t, resp.Errors)
+	assert.JSONEq(t, `{"livePlayers": [{"nickName": "p1"}], "liveTeams": [{"id": "100", "name": "Team A"}]}`, string(resp.Data))
+}
+
+func TestGetQuery(t *testing.T) {
+	live := new(mockLiveService)
+	h := graphqlapi.NewHandler(new(mockAbiosClient), live)
+
+	live.On("GetLiveTeams", mock.Anything, []string{"cs2"}).Return([]models.Team{{ID: 100, Name: "Team A"}}, nil)
+
+	query := url.Values{
+		"query":     {`query Teams($games: [String!]) { liveTeams(games: $games) { name } }`},
+		"variables": {`{"games": ["cs2"]}`},
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data": {"liveTeams": [{"name": "Team A"}]}}`, w.Body.String())
+}
+
+func TestUnknownGame(t *testing.T) {
+	live := new(mockLiveService)
+	h := graphqlapi.NewHandler(new(mockAbiosClient), live)
+
+	live.On("GetLiveSeries", mock.Anything, []string{"chess"}).Return([]models.SeriesDetails(nil), fmt.Errorf("%w: %q", service.ErrUnknownGame, "chess"))
+
+	resp := post(t, h, `{ liveSeries(games: ["chess"]) { id } }`)
+	require.Len(t, resp.Errors, 1)
+	assert.Contains(t, resp.Errors[0].Message, `unknown game: "chess"`)
+}
+
+// introspectionQuery is what GraphiQL and codegen tools load the schema
+// with, from graphql-js' getIntrospectionQuery.
+const introspectionQuery = `
+query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType {
+    kind name ofType { kind name ofType { kind name ofType { kind name } } }
+  } } } }
+}
+`
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedError string
+	}{
+		{
+			name:          "Deepest Data Query",
+			query:         `{ liveSeries { rosters { team { name } players { id } } } }`,
+			expectedError: "",
+		},
+		{
+			name:          "Introspection",
+			query:         introspectionQuery,
+			expectedError: "",
+		},
+		{
+			name:          "Depth",
+			query:         `{ __schema { types { fields { type { fields { type { fields { type { fields { type { fields { type { fields { type { fields { name } } } } } } } } } } } } } } } }`,
+			expectedError: "exceeds max depth",
+		},
+		{
+			name: "Complexity",
+			query: `{
+				a: liveSeries { rosters { players { id nickName } team { id name } } }
+				b: liveSeries { rosters { players { id nickName } team { id name } } }
+				c: liveSeries { rosters { players { id nickName } team { id name } } }
+			}`,
+			expectedError: "query too complex",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			live := new(mockLiveService)
+			h := graphqlapi.NewHandler(new(mockAbiosClient), live)
+
+			live.On("GetLiveSeries", mock.Anything, mock.Anything).Return([]models.SeriesDetails{}, nil).Maybe()
+
+			resp := post(t, h, tt.query)
+			if tt.expectedError == "" {
+				assert.Empty(t, resp.Errors)
+				return
+			}
+
+			require.NotEmpty(t, resp.Errors)
+			assert.Contains(t, resp.Errors[0].Message, tt.expectedError)
+		})
+	}
+}
+
+func TestRequestErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		body           string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "Missing Query", method: http.MethodPost, target: "/graphql", body: `{}`, expectedStatus: http.StatusBadRequest, expectedError: "missing query"},
+		{name: "Invalid Body", method: http.MethodPost, target: "/graphql", body: `{`, expectedStatus: http.StatusBadRequest, expectedError: "invalid request body: unexpected EOF"},
+		{name: "Invalid Variables", method: http.MethodGet, target: "/graphql?query={liveTeams{id}}&variables={", expectedStatus: http.StatusBadRequest, expectedError: "invalid variables: unexpected end of JSON input"},
+		{name: "Method", method: http.MethodPut, target: "/graphql", expectedStatus: http.StatusMethodNotAllowed, expectedError: "method not allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := graphqlapi.NewHandler(new(mockAbiosClient), new(mockLiveService))
+
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+			assert.JSONEq(t, fmt.Sprintf(`{"errors": [{"message": %q}]}`, tt.expectedError), w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package graphqlapi
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+)
+
+// loader batches lookups by ID onto a single upstream call. IDs known to be
+// needed soon are primed; the first load then fetches everything pending at
+// once, and later loads are served from what was already fetched. A loader
+// lives for one GraphQL request.
+type loader[T any] struct {
+	fetch  func(ctx context.Context, ids []int) ([]T, error)
+	idOf   func(T) int
+	onLoad func(items []T)
+
+	mu      sync.Mutex
+	pending map[int]struct{}
+	fetched map[int]struct{}
+	items   map[int]T
+}
+
+func newLoader[T any](fetch func(ctx context.Context, ids []int) ([]T, error), idOf func(T) int) *loader[T] {
+	return &loader[T]{
+		fetch:   fetch,
+		idOf:    idOf,
+		pending: map[int]struct{}{},
+		fetched: map[int]struct{}{},
+		items:   map[int]T{},
+	}
+}
+
+// prime marks IDs to be fetched with the next batch.
+func (l *loader[T]) prime(ids []int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := l.fetched[id]; !ok {
+			l.pending[id] = struct{}{}
+		}
+	}
+}
+
+// load returns the items for ids in the same order, skipping IDs Abios does
+// not know. Concurrent loads wait for the batch in flight.
+func (l *loader[T]) load(ctx context.Context, ids []int) ([]T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := l.fetched[id]; !ok {
+			l.pending[id] = struct{}{}
+		}
+	}
+
+	if len(l.pending) > 0 {
+		batch := make([]int, 0, len(l.pending))
+		for id := range l.pending {
+			batch = append(batch, id)
+		}
+		slices.Sort(batch)
+
+		items, err := l.fetch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range batch {
+			l.fetched[id] = struct{}{}
+		}
+		clear(l.pending)
+
+		for _, item := range items {
+			l.items[l.idOf(item)] = item
+		}
+		if l.onLoad != nil {
+			l.onLoad(items)
+		}
+	}
+
+	out := make([]T, 0, len(ids))
+	for _, id := range ids {
+		if item, ok := l.items[id]; ok {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+// loaders holds the per-request loaders of every batched resource.
+type loaders struct {
+	rosters *loader[models.Roster]
+	teams   *loader[models.Team]
+	players *loader[models.Player]
+}
+
+func newLoaders(client abios.AbiosClient) *loaders {
+	l := &loaders{
+		rosters: newLoader(client.GetRostersByID, func(r models.Roster) int { return r.ID }),
+		teams:   newLoader(client.GetTeamsByID, func(t models.Team) int { return t.ID }),
+		players: newLoader(client.GetPlayersByID, func(p models.Player) int { return p.ID }),
+	}
+
+	// a fetched roster tells which teams and players may be asked for next
+	l.rosters.onLoad = func(rosters []models.Roster) {
+		var teamIDs, playerIDs []int
+		for _, r := range rosters {
+			teamIDs = append(teamIDs, r.TeamId.ID)
+			for _, p := range r.LineUp.Players {
+				playerIDs = append(playerIDs, p.ID)
+			}
+		}
+		l.teams.prime(teamIDs)
+		l.players.prime(playerIDs)
+	}
+
+	return l
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,210 @@
+package graphqlapi
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/internal/service"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const schemaSDL = `
+schema {
+	query: Query
+}
+
+type Query {
+	# Series currently live, optionally restricted to game slugs such as "cs2".
+	liveSeries(games: [String!]): [Series!]!
+	# Players on the rosters of live series.
+	livePlayers(games: [String!]): [Player!]!
+	# Teams of the rosters of live series.
+	liveTeams(games: [String!]): [Team!]!
+}
+
+type Series {
+	id: ID!
+	title: String!
+	game: Game
+	rosters: [Roster!]!
+}
+
+type Game {
+	id: ID!
+	title: String!
+	slug: String!
+}
+
+type Roster {
+	id: ID!
+	team: Team
+	players: [Player!]!
+}
+
+type Team {
+	id: ID!
+	name: String!
+}
+
+type Player {
+	id: ID!
+	nickName: String!
+}
+`
+
+type gamesArgs struct {
+	Games *[]string
+}
+
+func (a gamesArgs) games() []string {
+	if a.Games == nil {
+		return nil
+	}
+	return *a.Games
+}
+
+type rootResolver struct {
+	liveService service.LiveService
+}
+
+func (r *rootResolver) LiveSeries(ctx context.Context, args gamesArgs) ([]*seriesResolver, error) {
+	if err := chargeComplexity(ctx, true); err != nil {
+		return nil, err
+	}
+
+	series, err := r.liveService.GetLiveSeries(ctx, args.games())
+	if err != nil {
+		return nil, err
+	}
+
+	// every roster of every series goes into one batch
+	l := loadersFrom(ctx)
+	out := make([]*seriesResolver, len(series))
+	for i, sr := range series {
+		out[i] = &seriesResolver{series: sr}
+		l.rosters.prime(sr.RosterIDs)
+	}
+
+	return out, nil
+}
+
+func (r *rootResolver) LivePlayers(ctx context.Context, args gamesArgs) ([]*playerResolver, error) {
+	if err := chargeComplexity(ctx, true); err != nil {
+		return nil, err
+	}
+
+	players, err := r.liveService.GetLivePlayers(ctx, args.games())
+	if err != nil {
+		return nil, err
+	}
+
+	return playerResolvers(players), nil
+}
+
+func (r *rootResolver) LiveTeams(ctx context.Context, args gamesArgs) ([]*teamResolver, error) {
+	if err := chargeComplexity(ctx, true); err != nil {
+		return nil, err
+	}
+
+	teams, err := r.liveService.GetLiveTeams(ctx, args.games())
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*teamResolver, len(teams))
+	for i, t := range teams {
+		out[i] = &teamResolver{team: t}
+	}
+	return out, nil
+}
+
+type seriesResolver struct {
+	series models.SeriesDetails
+}
+
+func (r *seriesResolver) ID() graphql.ID { return id(r.series.ID) }
+func (r *seriesResolver) Title() string  { return r.series.Title }
+
+func (r *seriesResolver) Game() *gameResolver {
+	if r.series.Game == nil {
+		return nil
+	}
+	return &gameResolver{game: *r.series.Game}
+}
+
+func (r *seriesResolver) Rosters(ctx context.Context) ([]*rosterResolver, error) {
+	rosters, err := loadersFrom(ctx).rosters.load(ctx, r.series.RosterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*rosterResolver, len(rosters))
+	for i, roster := range rosters {
+		out[i] = &rosterResolver{roster: roster}
+	}
+	return out, nil
+}
+
+type gameResolver struct {
+	game models.Game
+}
+
+func (r *gameResolver) ID() graphql.ID { return id(r.game.ID) }
+func (r *gameResolver) Title() string  { return r.game.Title }
+func (r *gameResolver) Slug() string   { return r.game.Slug }
+
+type rosterResolver struct {
+	roster models.Roster
+}
+
+func (r *rosterResolver) ID() graphql.ID { return id(r.roster.ID) }
+
+func (r *rosterResolver) Team(ctx context.Context) (*teamResolver, error) {
+	teams, err := loadersFrom(ctx).teams.load(ctx, []int{r.roster.TeamId.ID})
+	if err != nil || len(teams) == 0 {
+		return nil, err
+	}
+	return &teamResolver{team: teams[0]}, nil
+}
+
+func (r *rosterResolver) Players(ctx context.Context) ([]*playerResolver, error) {
+	ids := make([]int, len(r.roster.LineUp.Players))
+	for i, p := range r.roster.LineUp.Players {
+		ids[i] = p.ID
+	}
+
+	players, err := loadersFrom(ctx).players.load(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return playerResolvers(players), nil
+}
+
+type teamResolver struct {
+	team models.Team
+}
+
+func (r *teamResolver) ID() graphql.ID { return id(r.team.ID) }
+func (r *teamResolver) Name() string   { return r.team.Name }
+
+type playerResolver struct {
+	player models.Player
+}
+
+func (r *playerResolver) ID() graphql.ID   { return id(r.player.ID) }
+func (r *playerResolver) NickName() string { return r.player.Nickname }
+
+// id is an Abios ID as a GraphQL ID, which is serialized as a string so
+// that no ID is too large for a client.
+func id(n int) graphql.ID {
+	return graphql.ID(strconv.Itoa(n))
+}
+
+func playerResolvers(players []models.Player) []*playerResolver {
+	out := make([]*playerResolver, len(players))
+	for i, p := range players {
+		out[i] = &playerResolver{player: p}
+	}
+	return out
+}
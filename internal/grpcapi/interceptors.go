@@ -0,0 +1,33 @@
+package grpcapi
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// rateLimitUnaryInterceptor: the gRPC counterpart of the HTTP rate limit
+// middleware, rejecting calls over budget instead of queueing them.
+func rateLimitUnaryInterceptor(limiter *rate.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !limiter.Allow() {
+			return nil, status.Error(codes.ResourceExhausted, "Too Many Requests")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitStreamInterceptor charges a stream once when it is opened.
+func rateLimitStreamInterceptor(limiter *rate.Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !limiter.Allow() {
+			return status.Error(codes.ResourceExhausted, "Too Many Requests")
+		}
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,713 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: livev1/live.proto
+
+package livev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Game struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Slug          string                 `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Game) Reset() {
+	*x = Game{}
+	mi := &file_livev1_live_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Game) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Game) ProtoMessage() {}
+
+func (x *Game) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Game.ProtoReflect.Descriptor instead.
+func (*Game) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Game) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Game) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Game) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type Series struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	// unset when Abios does not know the game of the series
+	Game          *Game `protobuf:"bytes,3,opt,name=game,proto3" json:"game,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Series) Reset() {
+	*x = Series{}
+	mi := &file_livev1_live_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Series) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Series) ProtoMessage() {}
+
+func (x *Series) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Series.ProtoReflect.Descriptor instead.
+func (*Series) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Series) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Series) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Series) GetGame() *Game {
+	if x != nil {
+		return x.Game
+	}
+	return nil
+}
+
+type Player struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	NickName      string                 `protobuf:"bytes,2,opt,name=nick_name,json=nickName,proto3" json:"nick_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Player) Reset() {
+	*x = Player{}
+	mi := &file_livev1_live_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Player) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Player) ProtoMessage() {}
+
+func (x *Player) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Player.ProtoReflect.Descriptor instead.
+func (*Player) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Player) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Player) GetNickName() string {
+	if x != nil {
+		return x.NickName
+	}
+	return ""
+}
+
+type Team struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Team) Reset() {
+	*x = Team{}
+	mi := &file_livev1_live_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Team) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Team) ProtoMessage() {}
+
+func (x *Team) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Team.ProtoReflect.Descriptor instead.
+func (*Team) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Team) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Team) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ListLiveSeriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// game slugs such as "cs2" or "dota2", empty for every game
+	Games         []string `protobuf:"bytes,1,rep,name=games,proto3" json:"games,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLiveSeriesRequest) Reset() {
+	*x = ListLiveSeriesRequest{}
+	mi := &file_livev1_live_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLiveSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLiveSeriesRequest) ProtoMessage() {}
+
+func (x *ListLiveSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLiveSeriesRequest.ProtoReflect.Descriptor instead.
+func (*ListLiveSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListLiveSeriesRequest) GetGames() []string {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+type ListLiveSeriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Series        []*Series              `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLiveSeriesResponse) Reset() {
+	*x = ListLiveSeriesResponse{}
+	mi := &file_livev1_live_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLiveSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLiveSeriesResponse) ProtoMessage() {}
+
+func (x *ListLiveSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLiveSeriesResponse.ProtoReflect.Descriptor instead.
+func (*ListLiveSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListLiveSeriesResponse) GetSeries() []*Series {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+type ListLivePlayersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Games         []string               `protobuf:"bytes,1,rep,name=games,proto3" json:"games,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLivePlayersRequest) Reset() {
+	*x = ListLivePlayersRequest{}
+	mi := &file_livev1_live_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLivePlayersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLivePlayersRequest) ProtoMessage() {}
+
+func (x *ListLivePlayersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLivePlayersRequest.ProtoReflect.Descriptor instead.
+func (*ListLivePlayersRequest) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListLivePlayersRequest) GetGames() []string {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+type ListLivePlayersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Players       []*Player              `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLivePlayersResponse) Reset() {
+	*x = ListLivePlayersResponse{}
+	mi := &file_livev1_live_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLivePlayersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLivePlayersResponse) ProtoMessage() {}
+
+func (x *ListLivePlayersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLivePlayersResponse.ProtoReflect.Descriptor instead.
+func (*ListLivePlayersResponse) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListLivePlayersResponse) GetPlayers() []*Player {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+type ListLiveTeamsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Games         []string               `protobuf:"bytes,1,rep,name=games,proto3" json:"games,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLiveTeamsRequest) Reset() {
+	*x = ListLiveTeamsRequest{}
+	mi := &file_livev1_live_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLiveTeamsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLiveTeamsRequest) ProtoMessage() {}
+
+func (x *ListLiveTeamsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLiveTeamsRequest.ProtoReflect.Descriptor instead.
+func (*ListLiveTeamsRequest) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListLiveTeamsRequest) GetGames() []string {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+type ListLiveTeamsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Teams         []*Team                `protobuf:"bytes,1,rep,name=teams,proto3" json:"teams,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLiveTeamsResponse) Reset() {
+	*x = ListLiveTeamsResponse{}
+	mi := &file_livev1_live_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLiveTeamsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLiveTeamsResponse) ProtoMessage() {}
+
+func (x *ListLiveTeamsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLiveTeamsResponse.ProtoReflect.Descriptor instead.
+func (*ListLiveTeamsResponse) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListLiveTeamsResponse) GetTeams() []*Team {
+	if x != nil {
+		return x.Teams
+	}
+	return nil
+}
+
+type WatchLiveSeriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Games         []string               `protobuf:"bytes,1,rep,name=games,proto3" json:"games,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchLiveSeriesRequest) Reset() {
+	*x = WatchLiveSeriesRequest{}
+	mi := &file_livev1_live_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchLiveSeriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchLiveSeriesRequest) ProtoMessage() {}
+
+func (x *WatchLiveSeriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchLiveSeriesRequest.ProtoReflect.Descriptor instead.
+func (*WatchLiveSeriesRequest) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *WatchLiveSeriesRequest) GetGames() []string {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+type WatchLiveSeriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Series        []*Series              `protobuf:"bytes,1,rep,name=series,proto3" json:"series,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchLiveSeriesResponse) Reset() {
+	*x = WatchLiveSeriesResponse{}
+	mi := &file_livev1_live_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchLiveSeriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchLiveSeriesResponse) ProtoMessage() {}
+
+func (x *WatchLiveSeriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_livev1_live_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchLiveSeriesResponse.ProtoReflect.Descriptor instead.
+func (*WatchLiveSeriesResponse) Descriptor() ([]byte, []int) {
+	return file_livev1_live_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchLiveSeriesResponse) GetSeries() []*Series {
+	if x != nil {
+		return x.Series
+	}
+	return nil
+}
+
+var File_livev1_live_proto protoreflect.FileDescriptor
+
+const file_livev1_live_proto_rawDesc = "" +
+	"\n" +
+	"\x11livev1/live.proto\x12\rabios.live.v1\"@\n" +
+	"\x04Game\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04slug\x18\x03 \x01(\tR\x04slug\"W\n" +
+	"\x06Series\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12'\n" +
+	"\x04game\x18\x03 \x01(\v2\x13.abios.live.v1.GameR\x04game\"5\n" +
+	"\x06Player\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tnick_name\x18\x02 \x01(\tR\bnickName\"*\n" +
+	"\x04Team\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"-\n" +
+	"\x15ListLiveSeriesRequest\x12\x14\n" +
+	"\x05games\x18\x01 \x03(\tR\x05games\"G\n" +
+	"\x16ListLiveSeriesResponse\x12-\n" +
+	"\x06series\x18\x01 \x03(\v2\x15.abios.live.v1.SeriesR\x06series\".\n" +
+	"\x16ListLivePlayersRequest\x12\x14\n" +
+	"\x05games\x18\x01 \x03(\tR\x05games\"J\n" +
+	"\x17ListLivePlayersResponse\x12/\n" +
+	"\aplayers\x18\x01 \x03(\v2\x15.abios.live.v1.PlayerR\aplayers\",\n" +
+	"\x14ListLiveTeamsRequest\x12\x14\n" +
+	"\x05games\x18\x01 \x03(\tR\x05games\"B\n" +
+	"\x15ListLiveTeamsResponse\x12)\n" +
+	"\x05teams\x18\x01 \x03(\v2\x13.abios.live.v1.TeamR\x05teams\".\n" +
+	"\x16WatchLiveSeriesRequest\x12\x14\n" +
+	"\x05games\x18\x01 \x03(\tR\x05games\"H\n" +
+	"\x17WatchLiveSeriesResponse\x12-\n" +
+	"\x06series\x18\x01 \x03(\v2\x15.abios.live.v1.SeriesR\x06series2\x8e\x03\n" +
+	"\vLiveService\x12]\n" +
+	"\x0eListLiveSeries\x12$.abios.live.v1.ListLiveSeriesRequest\x1a%.abios.live.v1.ListLiveSeriesResponse\x12`\n" +
+	"\x0fListLivePlayers\x12%.abios.live.v1.ListLivePlayersRequest\x1a&.abios.live.v1.ListLivePlayersResponse\x12Z\n" +
+	"\rListLiveTeams\x12#.abios.live.v1.ListLiveTeamsRequest\x1a$.abios.live.v1.ListLiveTeamsResponse\x12b\n" +
+	"\x0fWatchLiveSeries\x12%.abios.live.v1.WatchLiveSeriesRequest\x1a&.abios.live.v1.WatchLiveSeriesResponse0\x01BEZCgithub.com/benjaminmishra/abios-apis/internal/grpcapi/livev1;livev1b\x06proto3"
+
+var (
+	file_livev1_live_proto_rawDescOnce sync.Once
+	file_livev1_live_proto_rawDescData []byte
+)
+
+func file_livev1_live_proto_rawDescGZIP() []byte {
+	file_livev1_live_proto_rawDescOnce.Do(func() {
+		file_livev1_live_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_livev1_live_proto_rawDesc), len(file_livev1_live_proto_rawDesc)))
+	})
+	return file_livev1_live_proto_rawDescData
+}
+
+var file_livev1_live_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_livev1_live_proto_goTypes = []any{
+	(*Game)(nil),                    // 0: abios.live.v1.Game
+	(*Series)(nil),                  // 1: abios.live.v1.Series
+	(*Player)(nil),                  // 2: abios.live.v1.Player
+	(*Team)(nil),                    // 3: abios.live.v1.Team
+	(*ListLiveSeriesRequest)(nil),   // 4: abios.live.v1.ListLiveSeriesRequest
+	(*ListLiveSeriesResponse)(nil),  // 5: abios.live.v1.ListLiveSeriesResponse
+	(*ListLivePlayersRequest)(nil),  // 6: abios.live.v1.ListLivePlayersRequest
+	(*ListLivePlayersResponse)(nil), // 7: abios.live.v1.ListLivePlayersResponse
+	(*ListLiveTeamsRequest)(nil),    // 8: abios.live.v1.ListLiveTeamsRequest
+	(*ListLiveTeamsResponse)(nil),   // 9: abios.live.v1.ListLiveTeamsResponse
+	(*WatchLiveSeriesRequest)(nil),  // 10: abios.live.v1.WatchLiveSeriesRequest
+	(*WatchLiveSeriesResponse)(nil), // 11: abios.live.v1.WatchLiveSeriesResponse
+}
+var file_livev1_live_proto_depIdxs = []int32{
+	0,  // 0: abios.live.v1.Series.game:type_name -> abios.live.v1.Game
+	1,  // 1: abios.live.v1.ListLiveSeriesResponse.series:type_name -> abios.live.v1.Series
+	2,  // 2: abios.live.v1.ListLivePlayersResponse.players:type_name -> abios.live.v1.Player
+	3,  // 3: abios.live.v1.ListLiveTeamsResponse.teams:type_name -> abios.live.v1.Team
+	1,  // 4: abios.live.v1.WatchLiveSeriesResponse.series:type_name -> abios.live.v1.Series
+	4,  // 5: abios.live.v1.LiveService.ListLiveSeries:input_type -> abios.live.v1.ListLiveSeriesRequest
+	6,  // 6: abios.live.v1.LiveService.ListLivePlayers:input_type -> abios.live.v1.ListLivePlayersRequest
+	8,  // 7: abios.live.v1.LiveService.ListLiveTeams:input_type -> abios.live.v1.ListLiveTeamsRequest
+	10, // 8: abios.live.v1.LiveService.WatchLiveSeries:input_type -> abios.live.v1.WatchLiveSeriesRequest
+	5,  // 9: abios.live.v1.LiveService.ListLiveSeries:output_type -> abios.live.v1.ListLiveSeriesResponse
+	7,  // 10: abios.live.v1.LiveService.ListLivePlayers:output_type -> abios.live.v1.ListLivePlayersResponse
+	9,  // 11: abios.live.v1.LiveService.ListLiveTeams:output_type -> abios.live.v1.ListLiveTeamsResponse
+	11, // 12: abios.live.v1.LiveService.WatchLiveSeries:output_type -> abios.live.v1.WatchLiveSeriesResponse
+	9,  // [9:13] is the sub-list for method output_type
+	5,  // [5:9] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_livev1_live_proto_init() }
+func file_livev1_live_proto_init() {
+	if File_livev1_live_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_livev1_live_proto_rawDesc), len(file_livev1_live_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   12,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_livev1_live_proto_goTypes,
+		DependencyIndexes: file_livev1_live_proto_depIdxs,
+		MessageInfos:      file_livev1_live_proto_msgTypes,
+	}.Build()
+	File_livev1_live_proto = out.File
+	file_livev1_live_proto_goTypes = nil
+	file_livev1_live_proto_depIdxs = nil
+}
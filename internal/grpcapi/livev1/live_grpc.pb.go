@@ -0,0 +1,247 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: livev1/live.proto
+
+package livev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LiveService_ListLiveSeries_FullMethodName  = "/abios.live.v1.LiveService/ListLiveSeries"
+	LiveService_ListLivePlayers_FullMethodName = "/abios.live.v1.LiveService/ListLivePlayers"
+	LiveService_ListLiveTeams_FullMethodName   = "/abios.live.v1.LiveService/ListLiveTeams"
+	LiveService_WatchLiveSeries_FullMethodName = "/abios.live.v1.LiveService/WatchLiveSeries"
+)
+
+// LiveServiceClient is the client API for LiveService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LiveService mirrors the live HTTP endpoints of the server.
+type LiveServiceClient interface {
+	ListLiveSeries(ctx context.Context, in *ListLiveSeriesRequest, opts ...grpc.CallOption) (*ListLiveSeriesResponse, error)
+	ListLivePlayers(ctx context.Context, in *ListLivePlayersRequest, opts ...grpc.CallOption) (*ListLivePlayersResponse, error)
+	ListLiveTeams(ctx context.Context, in *ListLiveTeamsRequest, opts ...grpc.CallOption) (*ListLiveTeamsResponse, error)
+	// WatchLiveSeries sends the current live series, then a new snapshot
+	// every time the set of live series changes.
+	WatchLiveSeries(ctx context.Context, in *WatchLiveSeriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchLiveSeriesResponse], error)
+}
+
+type liveServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLiveServiceClient(cc grpc.ClientConnInterface) LiveServiceClient {
+	return &liveServiceClient{cc}
+}
+
+func (c *liveServiceClient) ListLiveSeries(ctx context.Context, in *ListLiveSeriesRequest, opts ...grpc.CallOption) (*ListLiveSeriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLiveSeriesResponse)
+	err := c.cc.Invoke(ctx, LiveService_ListLiveSeries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liveServiceClient) ListLivePlayers(ctx context.Context, in *ListLivePlayersRequest, opts ...grpc.CallOption) (*ListLivePlayersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLivePlayersResponse)
+	err := c.cc.Invoke(ctx, LiveService_ListLivePlayers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liveServiceClient) ListLiveTeams(ctx context.Context, in *ListLiveTeamsRequest, opts ...grpc.CallOption) (*ListLiveTeamsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLiveTeamsResponse)
+	err := c.cc.Invoke(ctx, LiveService_ListLiveTeams_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *liveServiceClient) WatchLiveSeries(ctx context.Context, in *WatchLiveSeriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchLiveSeriesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LiveService_ServiceDesc.Streams[0], LiveService_WatchLiveSeries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchLiveSeriesRequest, WatchLiveSeriesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LiveService_WatchLiveSeriesClient = grpc.ServerStreamingClient[WatchLiveSeriesResponse]
+
+// LiveServiceServer is the server API for LiveService service.
+// All implementations must embed UnimplementedLiveServiceServer
+// for forward compatibility.
+//
+// LiveService mirrors the live HTTP endpoints of the server.
+type LiveServiceServer interface {
+	ListLiveSeries(context.Context, *ListLiveSeriesRequest) (*ListLiveSeriesResponse, error)
+	ListLivePlayers(context.Context, *ListLivePlayersRequest) (*ListLivePlayersResponse, error)
+	ListLiveTeams(context.Context, *ListLiveTeamsRequest) (*ListLiveTeamsResponse, error)
+	// WatchLiveSeries sends the current live series, then a new snapshot
+	// every time the set of live series changes.
+	WatchLiveSeries(*WatchLiveSeriesRequest, grpc.ServerStreamingServer[WatchLiveSeriesResponse]) error
+	mustEmbedUnimplementedLiveServiceServer()
+}
+
+// UnimplementedLiveServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLiveServiceServer struct{}
+
+func (UnimplementedLiveServiceServer) ListLiveSeries(context.Context, *ListLiveSeriesRequest) (*ListLiveSeriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLiveSeries not implemented")
+}
+func (UnimplementedLiveServiceServer) ListLivePlayers(context.Context, *ListLivePlayersRequest) (*ListLivePlayersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLivePlayers not implemented")
+}
+func (UnimplementedLiveServiceServer) ListLiveTeams(context.Context, *ListLiveTeamsRequest) (*ListLiveTeamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLiveTeams not implemented")
+}
+func (UnimplementedLiveServiceServer) WatchLiveSeries(*WatchLiveSeriesRequest, grpc.ServerStreamingServer[WatchLiveSeriesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLiveSeries not implemented")
+}
+func (UnimplementedLiveServiceServer) mustEmbedUnimplementedLiveServiceServer() {}
+func (UnimplementedLiveServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeLiveServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LiveServiceServer will
+// result in compilation errors.
+type UnsafeLiveServiceServer interface {
+	mustEmbedUnimplementedLiveServiceServer()
+}
+
+func RegisterLiveServiceServer(s grpc.ServiceRegistrar, srv LiveServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLiveServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LiveService_ServiceDesc, srv)
+}
+
+func _LiveService_ListLiveSeries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLiveSeriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiveServiceServer).ListLiveSeries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiveService_ListLiveSeries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiveServiceServer).ListLiveSeries(ctx, req.(*ListLiveSeriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiveService_ListLivePlayers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLivePlayersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiveServiceServer).ListLivePlayers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiveService_ListLivePlayers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiveServiceServer).ListLivePlayers(ctx, req.(*ListLivePlayersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiveService_ListLiveTeams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLiveTeamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LiveServiceServer).ListLiveTeams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LiveService_ListLiveTeams_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LiveServiceServer).ListLiveTeams(ctx, req.(*ListLiveTeamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LiveService_WatchLiveSeries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchLiveSeriesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LiveServiceServer).WatchLiveSeries(m, &grpc.GenericServerStream[WatchLiveSeriesRequest, WatchLiveSeriesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LiveService_WatchLiveSeriesServer = grpc.ServerStreamingServer[WatchLiveSeriesResponse]
+
+// LiveService_ServiceDesc is the grpc.ServiceDesc for LiveService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LiveService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "abios.live.v1.LiveService",
+	HandlerType: (*LiveServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListLiveSeries",
+			Handler:    _LiveService_ListLiveSeries_Handler,
+		},
+		{
+			MethodName: "ListLivePlayers",
+			Handler:    _LiveService_ListLivePlayers_Handler,
+		},
+		{
+			MethodName: "ListLiveTeams",
+			Handler:    _LiveService_ListLiveTeams_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchLiveSeries",
+			Handler:       _LiveService_WatchLiveSeries_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "livev1/live.proto",
+}
@@ -0,0 +1,222 @@
+// Package grpcapi serves the live data over gRPC, mirroring the HTTP API.
+package grpcapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative livev1/live.proto
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/grpcapi/livev1"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/internal/service"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+type Server struct {
+	livev1.UnimplementedLiveServiceServer
+
+	addr        string
+	liveService service.LiveService
+	grpcServer  *grpc.Server
+	logger      *log.Logger
+
+	mu            sync.Mutex
+	watchInterval time.Duration
+	// intervalChanged is closed when watchInterval changes
+	intervalChanged chan struct{}
+}
+
+// NewServer builds the gRPC server. The limiter is meant to be shared with
+// the HTTP server so both count against one inbound budget. WatchLiveSeries
+// polls the live service every watchInterval. The server's log lines go to
+// logger.
+func NewServer(addr string, s service.LiveService, limiter *rate.Limiter, watchInterval time.Duration, logger *log.Logger) *Server {
+	srv := &Server{
+		addr:        addr,
+		liveService: s,
+		logger:      logger,
+
+		watchInterval:   watchInterval,
+		intervalChanged: make(chan struct{}),
+	}
+
+	srv.grpcServer = grpc.NewServer(
+		grpc.ChainUnaryInterceptor(rateLimitUnaryInterceptor(limiter)),
+		grpc.ChainStreamInterceptor(rateLimitStreamInterceptor(limiter)),
+	)
+	livev1.RegisterLiveServiceServer(srv.grpcServer, srv)
+
+	return srv
+}
+
+// SetWatchInterval changes how often WatchLiveSeries polls, for the
+// streams already open too.
+func (s *Server) SetWatchInterval(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.watchInterval = d
+	close(s.intervalChanged)
+	s.intervalChanged = make(chan struct{})
+}
+
+// interval returns the watch interval and a channel closed when it changes.
+func (s *Server) interval() (time.Duration, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.watchInterval, s.intervalChanged
+}
+
+func (s *Server) Start() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(lis)
+}
+
+// Addr returns the address Start listens on.
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Serve accepts connections on an existing listener.
+func (s *Server) Serve(lis net.Listener) error {
+	s.logger.Printf("grpc server listening on %s", lis.Addr())
+	return s.grpcServer.Serve(lis)
+}
+
+// Stop drains in-flight calls, cutting them off when ctx expires. Watch
+// streams end as soon as their context is cancelled.
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Println("shutting down grpc server...")
+
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) ListLiveSeries(ctx context.Context, req *livev1.ListLiveSeriesRequest) (*livev1.ListLiveSeriesResponse, error) {
+	series, err := s.liveService.GetLiveSeries(ctx, req.GetGames())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &livev1.ListLiveSeriesResponse{Series: toProtoSeries(series)}, nil
+}
+
+func (s *Server) ListLivePlayers(ctx context.Context, req *livev1.ListLivePlayersRequest) (*livev1.ListLivePlayersResponse, error) {
+	players, err := s.liveService.GetLivePlayers(ctx, req.GetGames())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &livev1.ListLivePlayersResponse{Players: make([]*livev1.Player, len(players))}
+	for i, p := range players {
+		resp.Players[i] = &livev1.Player{Id: int64(p.ID), NickName: p.Nickname}
+	}
+
+	return resp, nil
+}
+
+func (s *Server) ListLiveTeams(ctx context.Context, req *livev1.ListLiveTeamsRequest) (*livev1.ListLiveTeamsResponse, error) {
+	teams, err := s.liveService.GetLiveTeams(ctx, req.GetGames())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &livev1.ListLiveTeamsResponse{Teams: make([]*livev1.Team, len(teams))}
+	for i, t := range teams {
+		resp.Teams[i] = &livev1.Team{Id: int64(t.ID), Name: t.Name}
+	}
+
+	return resp, nil
+}
+
+func (s *Server) WatchLiveSeries(req *livev1.WatchLiveSeriesRequest, stream grpc.ServerStreamingServer[livev1.WatchLiveSeriesResponse]) error {
+	ctx := stream.Context()
+
+	interval, changed := s.interval()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// only the first snapshot has someone waiting on it; later polls give
+	// way to interactive calls at the Abios rate limit
+	pollCtx := ctx
+	background := abios.WithPriority(ctx, abios.PriorityBackground)
+
+	var last *livev1.WatchLiveSeriesResponse
+	for {
+		series, err := s.liveService.GetLiveSeries(pollCtx, req.GetGames())
+		if err != nil {
+			return toStatus(err)
+		}
+
+		// the first snapshot is always sent, later ones only when they differ
+		snapshot := &livev1.WatchLiveSeriesResponse{Series: toProtoSeries(series)}
+		if last == nil || !proto.Equal(last, snapshot) {
+			if err := stream.Send(snapshot); err != nil {
+				return err
+			}
+			last = snapshot
+		}
+		pollCtx = background
+
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			case <-ticker.C:
+				break wait
+			case <-changed:
+				interval, changed = s.interval()
+				ticker.Reset(interval)
+			}
+		}
+	}
+}
+
+func toProtoSeries(series []models.SeriesDetails) []*livev1.Series {
+	out := make([]*livev1.Series, len(series))
+	for i, sr := range series {
+		out[i] = &livev1.Series{Id: int64(sr.ID), Title: sr.Title}
+		if sr.Game != nil {
+			out[i].Game = &livev1.Game{Id: int64(sr.Game.ID), Title: sr.Game.Title, Slug: sr.Game.Slug}
+		}
+	}
+	return out
+}
+
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, service.ErrUnknownGame):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,215 @@
+package grpcapi_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/grpcapi"
+	"github.com/benjaminmishra/abios-apis/internal/grpcapi/livev1"
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/internal/service"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type mockLiveService struct {
+	mock.Mock
+}
+
+func (m *mockLiveService) GetLiveSeries(ctx context.Context, games []string) ([]models.SeriesDetails, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).([]models.SeriesDetails), args.Error(1)
+}
+
+func (m *mockLiveService) GetLivePlayers(ctx context.Context, games []string) ([]models.Player, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).([]models.Player), args.Error(1)
+}
+
+func (m *mockLiveService) GetLiveTeams(ctx context.Context, games []string) ([]models.Team, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).([]models.Team), args.Error(1)
+}
+
+// startServer serves srv over an in-memory listener and returns a client.
+func startServer(t *testing.T, s service.LiveService, limiter *rate.Limiter) livev1.LiveServiceClient {
+	t.Helper()
+
+	return serve(t, grpcapi.NewServer("", s, limiter, 10*time.Millisecond, log.New(io.Discard, "", 0)))
+}
+
+// serve runs srv over an in-memory connection until the test ends.
+func serve(t *testing.T, srv *grpcapi.Server) livev1.LiveServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(func() { _ = srv.Stop(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return livev1.NewLiveServiceClient(conn)
+}
+
+func TestListLive(t *testing.T) {
+	m := new(mockLiveService)
+	client := startServer(t, m, rate.NewLimiter(rate.Inf, 0))
+	ctx := context.Background()
+
+	m.On("GetLiveSeries", mock.Anything, []string{"cs2"}).Return([]models.SeriesDetails{
+		{ID: 1, Title: "Series 1", Game: &models.Game{ID: 5, Title: "Counter-Strike 2", Slug: "cs2"}},
+		{ID: 2, Title: "Series 2"},
+	}, nil)
+	m.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{{ID: 1, Nickname: "Player 1"}}, nil)
+	m.On("GetLiveTeams", mock.Anything, []string(nil)).Return([]models.Team{{ID: 1, Name: "Team 1"}}, nil)
+
+	series, err := client.ListLiveSeries(ctx, &livev1.ListLiveSeriesRequest{Games: []string{"cs2"}})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&livev1.ListLiveSeriesResponse{Series: []*livev1.Series{
+		{Id: 1, Title: "Series 1", Game: &livev1.Game{Id: 5, Title: "Counter-Strike 2", Slug: "cs2"}},
+		{Id: 2, Title: "Series 2"},
+	}}, series), series.String())
+
+	players, err := client.ListLivePlayers(ctx, &livev1.ListLivePlayersRequest{})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&livev1.ListLivePlayersResponse{Players: []*livev1.Player{{Id: 1, NickName: "Player 1"}}}, players))
+
+	teams, err := client.ListLiveTeams(ctx, &livev1.ListLiveTeamsRequest{})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&livev1.ListLiveTeamsResponse{Teams: []*livev1.Team{{Id: 1, Name: "Team 1"}}}, teams))
+}
+
+func TestErrorCodes(t *testing.T) {
+	m := new(mockLiveService)
+	client := startServer(t, m, rate.NewLimiter(rate.Inf, 0))
+	ctx := context.Background()
+
+	m.On("GetLiveTeams", mock.Anything, []string{"chess"}).Return([]models.Team(nil), fmt.Errorf("%w: %q", service.ErrUnknownGame, "chess"))
+	m.On("GetLiveTeams", mock.Anything, []string(nil)).Return([]models.Team(nil), fmt.Errorf("abios: unexpected status 500"))
+
+	_, err := client.ListLiveTeams(ctx, &livev1.ListLiveTeamsRequest{Games: []string{"chess"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.ListLiveTeams(ctx, &livev1.ListLiveTeamsRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestRateLimit(t *testing.T) {
+	m := new(mockLiveService)
+	client := startServer(t, m, rate.NewLimiter(rate.Every(time.Hour), 1))
+	ctx := context.Background()
+
+	m.On("GetLivePlayers", mock.Anything, []string(nil)).Return([]models.Player{}, nil)
+
+	_, err := client.ListLivePlayers(ctx, &livev1.ListLivePlayersRequest{})
+	require.NoError(t, err)
+
+	_, err = client.ListLivePlayers(ctx, &livev1.ListLivePlayersRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	stream, err := client.WatchLiveSeries(ctx, &livev1.WatchLiveSeriesRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestWatchLiveSeries(t *testing.T) {
+	m := new(mockLiveService)
+	client := startServer(t, m, rate.NewLimiter(rate.Inf, 0))
+
+	first := []models.SeriesDetails{{ID: 1, Title: "Series 1"}}
+	second := []models.SeriesDetails{{ID: 1, Title: "Series 1"}, {ID: 2, Title: "Series 2"}}
+
+	// unchanged polls in between must not produce messages
+	m.On("GetLiveSeries", mock.Anything, []string{"dota2"}).Return(first, nil).Times(3)
+	m.On("GetLiveSeries", mock.Anything, []string{"dota2"}).Return(second, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchLiveSeries(ctx, &livev1.WatchLiveSeriesRequest{Games: []string{"dota2"}})
+	require.NoError(t, err)
+
+	msg, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Len(t, msg.GetSeries(), 1)
+
+	msg, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Len(t, msg.GetSeries(), 2)
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestWatchLiveSeriesIntervalChanges(t *testing.T) {
+	m := new(mockLiveService)
+	srv := grpcapi.NewServer("", m, rate.NewLimiter(rate.Inf, 0), time.Hour, log.New(io.Discard, "", 0))
+	client := serve(t, srv)
+
+	m.On("GetLiveSeries", mock.Anything, []string(nil)).Return([]models.SeriesDetails{{ID: 1}}, nil).Once()
+	m.On("GetLiveSeries", mock.Anything, []string(nil)).Return([]models.SeriesDetails{{ID: 2}}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchLiveSeries(ctx, &livev1.WatchLiveSeriesRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	// the open stream stops waiting out the hour
+	srv.SetWatchInterval(10 * time.Millisecond)
+
+	msg, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), msg.GetSeries()[0].GetId())
+}
+
+func TestWatchLiveSeriesPollsInBackground(t *testing.T) {
+	m := new(mockLiveService)
+	client := startServer(t, m, rate.NewLimiter(rate.Inf, 0))
+
+	withPriority := func(p abios.Priority) any {
+		return mock.MatchedBy(func(ctx context.Context) bool { return abios.PriorityFrom(ctx) == p })
+	}
+
+	// the first snapshot is interactive, the polls after it background
+	m.On("GetLiveSeries", withPriority(abios.PriorityInteractive), []string(nil)).Return([]models.SeriesDetails{{ID: 1}}, nil).Once()
+	m.On("GetLiveSeries", withPriority(abios.PriorityBackground), []string(nil)).Return([]models.SeriesDetails{{ID: 2}}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.WatchLiveSeries(ctx, &livev1.WatchLiveSeriesRequest{})
+	require.NoError(t, err)
+
+	msg, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), msg.GetSeries()[0].GetId())
+
+	msg, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), msg.GetSeries()[0].GetId())
+}
@@ -1,44 +1,30 @@
+// Package models holds the types the wrapper serves. The upstream Abios types
+// are aliases of the SDK's in pkg/abios, so values pass between the two
+// without conversion.
 package models
 
-type Series struct {
-	ID           int           `json:"id"`
-	Title        string        `json:"title"`
-	Participants []Participant `json:"participants"`
-}
-
-type Participant struct {
-	Roster Roster `json:"roster"`
-}
-
-type Roster struct {
-	ID     int    `json:"id"`
-	TeamId TeamId `json:"team"`
-	LineUp LineUp `json:"line_up"`
-}
-
-type TeamId struct {
-	ID int `json:"id"`
-}
-
-type PlayerId struct {
-	ID int `json:"id"`
-}
-
-type Player struct {
-	ID       int    `json:"id"`
-	Nickname string `json:"nick_name"`
-}
-
-type LineUp struct {
-	Players []PlayerId `json:"players"`
-}
+import "github.com/benjaminmishra/abios-apis/pkg/abios"
+
+type (
+	Series      = abios.Series
+	Participant = abios.Participant
+	RosterId    = abios.RosterId
+	Roster      = abios.Roster
+	TeamId      = abios.TeamId
+	PlayerId    = abios.PlayerId
+	GameId      = abios.GameId
+	Game        = abios.Game
+	Player      = abios.Player
+	LineUp      = abios.LineUp
+	Team        = abios.Team
+)
 
 type SeriesDetails struct {
 	ID    int    `json:"id"`
 	Title string `json:"title"`
-}
+	Game  *Game  `json:"game,omitempty"`
 
-type Team struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	// RosterIDs are the rosters playing, in participant order. They are
+	// not served, only followed by the GraphQL API.
+	RosterIDs []int `json:"-"`
 }
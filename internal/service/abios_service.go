@@ -1,28 +1,56 @@
 package service
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/benjaminmishra/abios-apis/internal/abios"
 	models "github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
 )
 
+// ErrUnknownGame is returned when a requested game slug is not known to Abios.
+var ErrUnknownGame = errors.New("unknown game")
+
+// LiveService exposes the live data. The games argument holds game slugs
+// (e.g. "cs2", "dota2") to restrict the results to; empty means every game.
+//
+// Every method returns its items ordered by ascending ID, whatever order
+// Abios answers in, so identical upstream state yields identical responses.
 type LiveService interface {
-	GetLiveSeries(ctx context.Context) ([]models.SeriesDetails, error)
-	GetLivePlayers(ctx context.Context) ([]models.Player, error)
-	GetLiveTeams(ctx context.Context) ([]models.Team, error)
+	GetLiveSeries(ctx context.Context, games []string) ([]models.SeriesDetails, error)
+	GetLivePlayers(ctx context.Context, games []string) ([]models.Player, error)
+	GetLiveTeams(ctx context.Context, games []string) ([]models.Team, error)
 }
 
+const (
+	// catalogTTL is how long the game catalog is reused. Games are added
+	// rarely, and a slug or game ID missing from the catalog fetches it again
+	// early, at most once every catalogMinAge.
+	catalogTTL    = time.Hour
+	catalogMinAge = time.Minute
+)
+
 type abiosLiveService struct {
 	client abios.AbiosClient
+	now    func() time.Time
+
+	mu        sync.Mutex
+	catalog   []models.Game
+	fetchedAt time.Time
 }
 
 func NewAbiosLiveService(client abios.AbiosClient) *abiosLiveService {
-	return &abiosLiveService{client: client}
+	return &abiosLiveService{client: client, now: time.Now}
 }
 
-func (s *abiosLiveService) GetLiveSeries(ctx context.Context) ([]models.SeriesDetails, error) {
-	series, err := s.client.GetLiveSeries(ctx)
+func (s *abiosLiveService) GetLiveSeries(ctx context.Context, games []string) ([]models.SeriesDetails, error) {
+	series, catalog, catalogErr, err := s.liveSeries(ctx, games)
 	if err != nil {
 		return nil, err
 	}
@@ -31,32 +59,74 @@ func (s *abiosLiveService) GetLiveSeries(ctx context.Context) ([]models.SeriesDe
 		return nil, nil
 	}
 
+	if catalogErr == nil && slices.ContainsFunc(series, func(sr models.Series) bool { return sr.Game.ID != 0 && !hasGame(catalog, sr.Game.ID) }) {
+		// a game newer than the catalog
+		if refreshed, err := s.games(ctx, true); err == nil {
+			catalog = refreshed
+		}
+	}
+
+	gamesByID := make(map[int]models.Game, len(catalog))
+	for _, g := range catalog {
+		gamesByID[g.ID] = g
+	}
+
 	result := make([]models.SeriesDetails, len(series))
 	for i, sr := range series {
 		result[i] = models.SeriesDetails{
 			ID:    sr.ID,
 			Title: sr.Title,
 		}
+		if g, ok := gamesByID[sr.Game.ID]; ok {
+			result[i].Game = &g
+		}
+		for _, p := range sr.Participants {
+			result[i].RosterIDs = append(result[i].RosterIDs, p.Roster.ID)
+		}
 	}
+	slices.SortFunc(result, func(a, b models.SeriesDetails) int { return cmp.Compare(a.ID, b.ID) })
 
 	return result, nil
 }
 
-func (s *abiosLiveService) GetLivePlayers(ctx context.Context) ([]models.Player, error) {
-	series, err := s.client.GetLiveSeries(ctx)
+// liveSeries fetches the live series of games and the game catalog. The
+// catalog is needed both for the filter and to describe each series. Without
+// a filter it is fetched alongside the series, and a failure to fetch it is
+// left to the caller as catalogErr, so the series can go out without their
+// games.
+func (s *abiosLiveService) liveSeries(ctx context.Context, games []string) (series []models.Series, catalog []models.Game, catalogErr, err error) {
+	if len(games) > 0 {
+		catalog, gameIDs, err := s.resolveGames(ctx, games)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		series, err = s.client.GetLiveSeries(ctx, gameIDs)
+		return series, catalog, nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		catalog, catalogErr = s.games(ctx, false)
+	}()
+	series, err = s.client.GetLiveSeries(ctx, nil)
+	<-done
+
+	return series, catalog, catalogErr, err
+}
+
+func (s *abiosLiveService) GetLivePlayers(ctx context.Context, games []string) ([]models.Player, error) {
+	gameIDs, err := s.gameIDs(ctx, games)
 	if err != nil {
 		return nil, err
 	}
 
-	// collect roster IDs from live series
-	liveRosterIDs := []int{}
-	for _, sr := range series {
-		for _, p := range sr.Participants {
-			liveRosterIDs = append(liveRosterIDs, p.Roster.ID)
-		}
+	series, err := s.client.GetLiveSeries(ctx, gameIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	liveRosters, err := s.client.GetRostersByID(ctx, liveRosterIDs)
+	liveRosters, err := s.client.GetRostersByID(ctx, rosterIDs(series))
 	if err != nil {
 		return nil, err
 	}
@@ -74,25 +144,23 @@ func (s *abiosLiveService) GetLivePlayers(ctx context.Context) ([]models.Player,
 	if err != nil {
 		return nil, err
 	}
+	slices.SortFunc(result, func(a, b models.Player) int { return cmp.Compare(a.ID, b.ID) })
 
 	return result, nil
 }
 
-func (s *abiosLiveService) GetLiveTeams(ctx context.Context) ([]models.Team, error) {
-	series, err := s.client.GetLiveSeries(ctx)
+func (s *abiosLiveService) GetLiveTeams(ctx context.Context, games []string) ([]models.Team, error) {
+	gameIDs, err := s.gameIDs(ctx, games)
 	if err != nil {
 		return nil, err
 	}
 
-	// collect roster IDs
-	liveRosterIDs := []int{}
-	for _, sr := range series {
-		for _, p := range sr.Participants {
-			liveRosterIDs = append(liveRosterIDs, p.Roster.ID)
-		}
+	series, err := s.client.GetLiveSeries(ctx, gameIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	liveRosters, err := s.client.GetRostersByID(ctx, liveRosterIDs)
+	liveRosters, err := s.client.GetRostersByID(ctx, rosterIDs(series))
 	if err != nil {
 		return nil, err
 	}
@@ -108,14 +176,120 @@ func (s *abiosLiveService) GetLiveTeams(ctx context.Context) ([]models.Team, err
 	if err != nil {
 		return nil, err
 	}
+	slices.SortFunc(result, func(a, b models.Team) int { return cmp.Compare(a.ID, b.ID) })
 
 	return result, nil
 }
 
+// gameIDs resolves game slugs to Abios game IDs, skipping the catalog
+// lookup entirely when no games were requested.
+func (s *abiosLiveService) gameIDs(ctx context.Context, games []string) ([]int, error) {
+	if len(games) == 0 {
+		return nil, nil
+	}
+
+	_, ids, err := s.resolveGames(ctx, games)
+	return ids, err
+}
+
+// resolveGames returns the game catalog and the IDs of games in it. A slug
+// missing from the catalog fetches it again, in case the game is new.
+func (s *abiosLiveService) resolveGames(ctx context.Context, games []string) ([]models.Game, []int, error) {
+	catalog, err := s.games(ctx, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids, err := ResolveGameIDs(catalog, games)
+	if errors.Is(err, ErrUnknownGame) {
+		if catalog, err = s.games(ctx, true); err != nil {
+			return nil, nil, err
+		}
+		ids, err = ResolveGameIDs(catalog, games)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return catalog, ids, nil
+}
+
+// games returns the game catalog, fetched from Abios once every catalogTTL,
+// or once every catalogMinAge when stale is set. While a refresh fails the
+// last catalog fetched is returned, and the refresh tried again next time.
+func (s *abiosLiveService) games(ctx context.Context, stale bool) ([]models.Game, error) {
+	maxAge := catalogTTL
+	if stale {
+		maxAge = catalogMinAge
+	}
+
+	s.mu.Lock()
+	catalog, fetchedAt := s.catalog, s.fetchedAt
+	s.mu.Unlock()
+	if catalog != nil && s.now().Sub(fetchedAt) < maxAge {
+		return catalog, nil
+	}
+
+	fetched, err := s.client.GetGames(ctx)
+	if err != nil {
+		if catalog != nil {
+			return catalog, nil
+		}
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.catalog, s.fetchedAt = fetched, s.now()
+	s.mu.Unlock()
+
+	return fetched, nil
+}
+
+func hasGame(catalog []models.Game, id int) bool {
+	return slices.ContainsFunc(catalog, func(g models.Game) bool { return g.ID == id })
+}
+
+// ResolveGameIDs maps game slugs onto the IDs of the catalog, in ascending
+// order. It fails with ErrUnknownGame for slugs missing from the catalog.
+func ResolveGameIDs(catalog []models.Game, games []string) ([]int, error) {
+	if len(games) == 0 {
+		return nil, nil
+	}
+
+	idsBySlug := make(map[string]int, len(catalog))
+	for _, g := range catalog {
+		idsBySlug[strings.ToLower(g.Slug)] = g.ID
+	}
+
+	ids := map[int]struct{}{}
+	for _, slug := range games {
+		id, ok := idsBySlug[strings.ToLower(slug)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownGame, slug)
+		}
+		ids[id] = struct{}{}
+	}
+
+	return mapKeysToSlice(ids), nil
+}
+
+// rosterIDs collects the unique roster IDs taking part in the series.
+func rosterIDs(series []models.Series) []int {
+	ids := map[int]struct{}{}
+	for _, sr := range series {
+		for _, p := range sr.Participants {
+			ids[p.Roster.ID] = struct{}{}
+		}
+	}
+	return mapKeysToSlice(ids)
+}
+
+// mapKeysToSlice returns the keys in ascending order, so the ID filters sent
+// upstream are the same for the same set of IDs and cache well.
 func mapKeysToSlice(m map[int]struct{}) []int {
 	out := make([]int, 0, len(m))
 	for k := range m {
 		out = append(out, k)
 	}
+	slices.Sort(out)
 	return out
 }
@@ -2,7 +2,10 @@ package service_test
 
 import (
 	"context"
+	"errors"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/benjaminmishra/abios-apis/internal/models"
 	"github.com/benjaminmishra/abios-apis/internal/service"
@@ -14,11 +17,16 @@ type mockAbiosClient struct {
 	mock.Mock
 }
 
-func (m *mockAbiosClient) GetLiveSeries(ctx context.Context) ([]models.Series, error) {
-	args := m.Called(ctx)
+func (m *mockAbiosClient) GetLiveSeries(ctx context.Context, gameIDs []int) ([]models.Series, error) {
+	args := m.Called(ctx, gameIDs)
 	return args.Get(0).([]models.Series), args.Error(1)
 }
 
+func (m *mockAbiosClient) GetGames(ctx context.Context) ([]models.Game, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.Game), args.Error(1)
+}
+
 func (m *mockAbiosClient) GetRostersByID(ctx context.Context, ids []int) ([]models.Roster, error) {
 	args := m.Called(ctx, ids)
 	return args.Get(0).([]models.Roster), args.Error(1)
@@ -34,6 +42,11 @@ func (m *mockAbiosClient) GetTeamsByID(ctx context.Context, ids []int) ([]models
 	return args.Get(0).([]models.Team), args.Error(1)
 }
 
+var mockGames = []models.Game{
+	{ID: 1, Title: "Dota 2", Slug: "dota2"},
+	{ID: 5, Title: "Counter-Strike 2", Slug: "cs2"},
+}
+
 func TestNewAbiosLiveService(t *testing.T) {
 	mockClient := new(mockAbiosClient)
 	service := service.NewAbiosLiveService(mockClient)
@@ -49,6 +62,11 @@ func TestGetLiveSeries(t *testing.T) {
 		{
 			ID:    1,
 			Title: "Series 1",
+			Game:  models.GameId{ID: 5},
+			Participants: []models.Participant{
+				{Roster: models.RosterId{ID: 20}},
+				{Roster: models.RosterId{ID: 10}},
+			},
 		},
 		{
 			ID:    2,
@@ -56,13 +74,138 @@ func TestGetLiveSeries(t *testing.T) {
 		},
 	}
 
-	mockClient.On("GetLiveSeries", ctx).Return(mockSeries, nil)
+	mockClient.On("GetGames", ctx).Return(mockGames, nil)
+	mockClient.On("GetLiveSeries", ctx, []int(nil)).Return(mockSeries, nil)
 
-	result, err := service.GetLiveSeries(ctx)
+	result, err := service.GetLiveSeries(ctx, nil)
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
 	assert.Equal(t, mockSeries[0].ID, result[0].ID)
 	assert.Equal(t, mockSeries[0].Title, result[0].Title)
+	assert.Equal(t, &mockGames[1], result[0].Game)
+	assert.Equal(t, []int{20, 10}, result[0].RosterIDs)
+	assert.Nil(t, result[1].Game)
+	assert.Empty(t, result[1].RosterIDs)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetLiveSeriesGameFilter(t *testing.T) {
+	mockClient := new(mockAbiosClient)
+	s := service.NewAbiosLiveService(mockClient)
+	ctx := context.Background()
+
+	mockClient.On("GetGames", ctx).Return(mockGames, nil)
+	mockClient.On("GetLiveSeries", ctx, []int{1, 5}).Return([]models.Series{
+		{ID: 1, Title: "Series 1", Game: models.GameId{ID: 5}},
+	}, nil)
+
+	result, err := s.GetLiveSeries(ctx, []string{"CS2", "dota2", "cs2"})
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetLiveSeriesUnknownGame(t *testing.T) {
+	mockClient := new(mockAbiosClient)
+	s := service.NewAbiosLiveService(mockClient)
+	ctx := context.Background()
+
+	mockClient.On("GetGames", ctx).Return(mockGames, nil)
+
+	_, err := s.GetLiveSeries(ctx, []string{"chess"})
+	assert.ErrorIs(t, err, service.ErrUnknownGame)
+
+	mockClient.AssertNotCalled(t, "GetLiveSeries", mock.Anything, mock.Anything)
+}
+
+func TestGameCatalogIsReused(t *testing.T) {
+	mockClient := new(mockAbiosClient)
+	now := time.Now()
+	s := service.NewAbiosLiveServiceAt(mockClient, func() time.Time { return now })
+	ctx := context.Background()
+
+	mockClient.On("GetGames", ctx).Return(mockGames, nil).Once()
+	mockClient.On("GetLiveSeries", ctx, mock.Anything).Return([]models.Series{{ID: 1, Game: models.GameId{ID: 5}}}, nil)
+
+	for range 3 {
+		_, err := s.GetLiveSeries(ctx, nil)
+		assert.NoError(t, err)
+	}
+	_, err := s.GetLiveSeries(ctx, []string{"cs2"})
+	assert.NoError(t, err)
+
+	// and fetched again once it has aged
+	now = now.Add(time.Hour)
+	mockClient.On("GetGames", ctx).Return(mockGames, nil).Once()
+	_, err = s.GetLiveSeries(ctx, nil)
+	assert.NoError(t, err)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGameCatalogRefreshesForNewGames(t *testing.T) {
+	mockClient := new(mockAbiosClient)
+	now := time.Now()
+	s := service.NewAbiosLiveServiceAt(mockClient, func() time.Time { return now })
+	ctx := context.Background()
+
+	mockClient.On("GetGames", ctx).Return(mockGames, nil).Once()
+	_, err := s.GetLiveSeries(ctx, []string{"chess"})
+	assert.ErrorIs(t, err, service.ErrUnknownGame)
+
+	// a fresh catalog is not fetched again for unknown games
+	_, err = s.GetLiveSeries(ctx, []string{"chess"})
+	assert.ErrorIs(t, err, service.ErrUnknownGame)
+
+	// a minute on, a new game is looked up
+	now = now.Add(time.Minute)
+	withChess := append(slices.Clone(mockGames), models.Game{ID: 9, Title: "Chess", Slug: "chess"})
+	mockClient.On("GetGames", ctx).Return(withChess, nil).Once()
+	mockClient.On("GetLiveSeries", ctx, []int{9}).Return([]models.Series{{ID: 1, Game: models.GameId{ID: 9}}}, nil)
+
+	result, err := s.GetLiveSeries(ctx, []string{"chess"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Chess", result[0].Game.Title)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGameCatalogOutage(t *testing.T) {
+	mockClient := new(mockAbiosClient)
+	now := time.Now()
+	s := service.NewAbiosLiveServiceAt(mockClient, func() time.Time { return now })
+	ctx := context.Background()
+
+	outage := errors.New("upstream down")
+	live := []models.Series{{ID: 1, Title: "Series 1", Game: models.GameId{ID: 5}}}
+	mockClient.On("GetLiveSeries", ctx, mock.Anything).Return(live, nil)
+
+	// with no catalog yet the series go out without their games
+	mockClient.On("GetGames", ctx).Return([]models.Game(nil), outage).Once()
+	result, err := s.GetLiveSeries(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SeriesDetails{{ID: 1, Title: "Series 1"}}, result)
+
+	// a filter cannot do without
+	mockClient.On("GetGames", ctx).Return([]models.Game(nil), outage).Once()
+	_, err = s.GetLiveSeries(ctx, []string{"cs2"})
+	assert.ErrorIs(t, err, outage)
+
+	mockClient.On("GetGames", ctx).Return(mockGames, nil).Once()
+	_, err = s.GetLiveSeries(ctx, nil)
+	assert.NoError(t, err)
+
+	// once aged, a failed refresh keeps the last catalog, filter included
+	now = now.Add(time.Hour)
+	mockClient.On("GetGames", ctx).Return([]models.Game(nil), outage).Twice()
+	result, err = s.GetLiveSeries(ctx, []string{"cs2"})
+	assert.NoError(t, err)
+	assert.Equal(t, &mockGames[1], result[0].Game)
+	result, err = s.GetLiveSeries(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &mockGames[1], result[0].Game)
 
 	mockClient.AssertExpectations(t)
 }
@@ -78,12 +221,12 @@ func TestGetLivePlayers(t *testing.T) {
 			Title: "Series 1",
 			Participants: []models.Participant{
 				{
-					Roster: models.Roster{
+					Roster: models.RosterId{
 						ID: 10,
 					},
 				},
 				{
-					Roster: models.Roster{
+					Roster: models.RosterId{
 						ID: 20,
 					},
 				},
@@ -106,7 +249,7 @@ func TestGetLivePlayers(t *testing.T) {
 		},
 	}
 
-	mockClient.On("GetLiveSeries", ctx).Return(mockSeries, nil)
+	mockClient.On("GetLiveSeries", ctx, []int(nil)).Return(mockSeries, nil)
 	mockClient.On("GetRostersByID", ctx, []int{10, 20}).Return(mockRosters, nil)
 	mockClient.On("GetPlayersByID", ctx, []int{100, 101}).Return([]models.Player{
 		{
@@ -119,7 +262,7 @@ func TestGetLivePlayers(t *testing.T) {
 		},
 	}, nil)
 
-	result, err := service.GetLivePlayers(ctx)
+	result, err := service.GetLivePlayers(ctx, nil)
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
 
@@ -136,8 +279,8 @@ func TestGetLiveTeams(t *testing.T) {
 			ID:    1,
 			Title: "Series 1",
 			Participants: []models.Participant{
-				{Roster: models.Roster{ID: 10}},
-				{Roster: models.Roster{ID: 20}},
+				{Roster: models.RosterId{ID: 10}},
+				{Roster: models.RosterId{ID: 20}},
 			},
 		},
 	}
@@ -153,20 +296,91 @@ func TestGetLiveTeams(t *testing.T) {
 		},
 	}
 
-	mockClient.On("GetLiveSeries", ctx).Return(mockSeries, nil)
+	mockClient.On("GetGames", ctx).Return(mockGames, nil)
+	mockClient.On("GetLiveSeries", ctx, []int{5}).Return(mockSeries, nil)
 	mockClient.On("GetRostersByID", ctx, []int{10, 20}).Return(mockRosters, nil)
 
-	expectedTeamIDs := []int{100, 200}
-	mockClient.On("GetTeamsByID", ctx, mock.MatchedBy(func(ids []int) bool {
-		return assert.ElementsMatch(t, expectedTeamIDs, ids)
-	})).Return([]models.Team{
+	mockClient.On("GetTeamsByID", ctx, []int{100, 200}).Return([]models.Team{
 		{ID: 100, Name: "Team A"},
 		{ID: 200, Name: "Team B"},
 	}, nil)
 
-	result, err := s.GetLiveTeams(ctx)
+	result, err := s.GetLiveTeams(ctx, []string{"cs2"})
 	assert.NoError(t, err)
 	assert.Len(t, result, 2)
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestDeterministicOrdering(t *testing.T) {
+	ctx := context.Background()
+
+	// upstream answers in arbitrary order and repeats rosters across series
+	mockSeries := []models.Series{
+		{
+			ID: 7,
+			Participants: []models.Participant{
+				{Roster: models.RosterId{ID: 30}},
+				{Roster: models.RosterId{ID: 10}},
+			},
+		},
+		{
+			ID: 3,
+			Participants: []models.Participant{
+				{Roster: models.RosterId{ID: 20}},
+				{Roster: models.RosterId{ID: 10}},
+			},
+		},
+	}
+	mockRosters := []models.Roster{
+		{ID: 30, TeamId: models.TeamId{ID: 300}, LineUp: models.LineUp{Players: []models.PlayerId{{ID: 9}, {ID: 4}}}},
+		{ID: 10, TeamId: models.TeamId{ID: 100}, LineUp: models.LineUp{Players: []models.PlayerId{{ID: 6}, {ID: 1}}}},
+		{ID: 20, TeamId: models.TeamId{ID: 200}, LineUp: models.LineUp{Players: []models.PlayerId{{ID: 4}, {ID: 2}}}},
+	}
+
+	t.Run("Series", func(t *testing.T) {
+		mockClient := new(mockAbiosClient)
+		s := service.NewAbiosLiveService(mockClient)
+
+		mockClient.On("GetGames", ctx).Return(mockGames, nil)
+		mockClient.On("GetLiveSeries", ctx, []int(nil)).Return(mockSeries, nil)
+
+		result, err := s.GetLiveSeries(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3, 7}, []int{result[0].ID, result[1].ID})
+	})
+
+	t.Run("Players", func(t *testing.T) {
+		mockClient := new(mockAbiosClient)
+		s := service.NewAbiosLiveService(mockClient)
+
+		mockClient.On("GetLiveSeries", ctx, []int(nil)).Return(mockSeries, nil)
+		mockClient.On("GetRostersByID", ctx, []int{10, 20, 30}).Return(mockRosters, nil)
+		mockClient.On("GetPlayersByID", ctx, []int{1, 2, 4, 6, 9}).Return([]models.Player{
+			{ID: 6}, {ID: 2}, {ID: 9}, {ID: 1}, {ID: 4},
+		}, nil)
+
+		result, err := s.GetLivePlayers(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Player{{ID: 1}, {ID: 2}, {ID: 4}, {ID: 6}, {ID: 9}}, result)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Teams", func(t *testing.T) {
+		mockClient := new(mockAbiosClient)
+		s := service.NewAbiosLiveService(mockClient)
+
+		mockClient.On("GetLiveSeries", ctx, []int(nil)).Return(mockSeries, nil)
+		mockClient.On("GetRostersByID", ctx, []int{10, 20, 30}).Return(mockRosters, nil)
+		mockClient.On("GetTeamsByID", ctx, []int{100, 200, 300}).Return([]models.Team{
+			{ID: 300}, {ID: 100}, {ID: 200},
+		}, nil)
+
+		result, err := s.GetLiveTeams(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Team{{ID: 100}, {ID: 200}, {ID: 300}}, result)
+
+		mockClient.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	models "github.com/benjaminmishra/abios-apis/internal/models"
+)
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// cachedLiveService keeps the results of another LiveService for a short
+// time, so clients polling the live endpoints share upstream calls.
+type cachedLiveService struct {
+	next LiveService
+
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cacheEntry
+}
+
+func NewCachedLiveService(next LiveService, ttl time.Duration) *cachedLiveService {
+	return &cachedLiveService{
+		next:    next,
+		ttl:     ttl,
+		entries: map[string]cacheEntry{},
+	}
+}
+
+// CacheTTL reports how long results are served from the cache.
+func (s *cachedLiveService) CacheTTL() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ttl
+}
+
+// SetCacheTTL changes how long new results are kept. Results already
+// cached keep the expiry they were stored with.
+func (s *cachedLiveService) SetCacheTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ttl = ttl
+}
+
+func (s *cachedLiveService) GetLiveSeries(ctx context.Context, games []string) ([]models.SeriesDetails, error) {
+	return cached(s, "series", games, func() ([]models.SeriesDetails, error) {
+		return s.next.GetLiveSeries(ctx, games)
+	})
+}
+
+func (s *cachedLiveService) GetLivePlayers(ctx context.Context, games []string) ([]models.Player, error) {
+	return cached(s, "players", games, func() ([]models.Player, error) {
+		return s.next.GetLivePlayers(ctx, games)
+	})
+}
+
+func (s *cachedLiveService) GetLiveTeams(ctx context.Context, games []string) ([]models.Team, error) {
+	return cached(s, "teams", games, func() ([]models.Team, error) {
+		return s.next.GetLiveTeams(ctx, games)
+	})
+}
+
+// cached returns the stored result for the key or loads and stores it.
+// Errors are never cached. Results are shared between callers, who must not
+// modify them.
+func cached[T any](s *cachedLiveService, kind string, games []string, load func() ([]T, error)) ([]T, error) {
+	key := cacheKey(kind, games)
+
+	s.mu.Lock()
+	entry, ok := s.entries[key]
+	s.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		return entry.value.([]T), nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = cacheEntry{value: value, expires: now.Add(s.ttl)}
+	s.mu.Unlock()
+
+	return value, nil
+}
+
+// cacheKey normalizes the game list so "dota2,cs2" and "CS2,dota2" share
+// an entry.
+func cacheKey(kind string, games []string) string {
+	normalized := make([]string, len(games))
+	for i, g := range games {
+		normalized[i] = strings.ToLower(g)
+	}
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+
+	return kind + "?" + strings.Join(normalized, ",")
+}
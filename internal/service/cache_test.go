@@ -0,0 +1,112 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockLiveService struct {
+	mock.Mock
+}
+
+func (m *mockLiveService) GetLiveSeries(ctx context.Context, games []string) ([]models.SeriesDetails, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).([]models.SeriesDetails), args.Error(1)
+}
+
+func (m *mockLiveService) GetLivePlayers(ctx context.Context, games []string) ([]models.Player, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).([]models.Player), args.Error(1)
+}
+
+func (m *mockLiveService) GetLiveTeams(ctx context.Context, games []string) ([]models.Team, error) {
+	args := m.Called(ctx, games)
+	return args.Get(0).([]models.Team), args.Error(1)
+}
+
+func TestCachedLiveService(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mockLiveService)
+	s := service.NewCachedLiveService(inner, 50*time.Millisecond)
+
+	assert.Equal(t, 50*time.Millisecond, s.CacheTTL())
+
+	inner.On("GetLivePlayers", ctx, []string{"dota2", "cs2"}).Return([]models.Player{{ID: 1}}, nil).Once()
+	inner.On("GetLivePlayers", ctx, []string{"cs2"}).Return([]models.Player{{ID: 2}}, nil).Once()
+
+	// the same games in another order and case share the entry
+	for _, games := range [][]string{{"dota2", "cs2"}, {"CS2", "dota2"}} {
+		result, err := s.GetLivePlayers(ctx, games)
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Player{{ID: 1}}, result)
+	}
+
+	result, err := s.GetLivePlayers(ctx, []string{"cs2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Player{{ID: 2}}, result)
+
+	inner.AssertExpectations(t)
+
+	// reloaded once expired
+	inner.On("GetLivePlayers", ctx, []string{"cs2"}).Return([]models.Player{{ID: 3}}, nil).Once()
+	time.Sleep(60 * time.Millisecond)
+
+	result, err = s.GetLivePlayers(ctx, []string{"cs2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Player{{ID: 3}}, result)
+
+	inner.AssertExpectations(t)
+}
+
+func TestCachedLiveServiceDoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mockLiveService)
+	s := service.NewCachedLiveService(inner, time.Minute)
+
+	inner.On("GetLiveTeams", ctx, []string(nil)).Return([]models.Team(nil), errors.New("boom")).Once()
+	inner.On("GetLiveTeams", ctx, []string(nil)).Return([]models.Team{{ID: 1}}, nil).Once()
+
+	_, err := s.GetLiveTeams(ctx, nil)
+	assert.Error(t, err)
+
+	result, err := s.GetLiveTeams(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Team{{ID: 1}}, result)
+
+	result, err = s.GetLiveTeams(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Team{{ID: 1}}, result)
+
+	inner.AssertExpectations(t)
+}
+
+func TestCachedLiveServiceSetCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mockLiveService)
+	s := service.NewCachedLiveService(inner, 0)
+
+	// uncached at first, then loaded once more and kept for the new TTL
+	inner.On("GetLiveTeams", ctx, []string(nil)).Return([]models.Team{{ID: 1}}, nil).Times(3)
+
+	for range 2 {
+		_, err := s.GetLiveTeams(ctx, nil)
+		assert.NoError(t, err)
+	}
+
+	s.SetCacheTTL(time.Minute)
+	assert.Equal(t, time.Minute, s.CacheTTL())
+
+	for range 2 {
+		_, err := s.GetLiveTeams(ctx, nil)
+		assert.NoError(t, err)
+	}
+
+	inner.AssertExpectations(t)
+}
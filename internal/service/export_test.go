@@ -0,0 +1,14 @@
+package service
+
+import (
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+)
+
+// NewAbiosLiveServiceAt is NewAbiosLiveService telling the time with now.
+func NewAbiosLiveServiceAt(client abios.AbiosClient, now func() time.Time) *abiosLiveService {
+	s := NewAbiosLiveService(client)
+	s.now = now
+	return s
+}
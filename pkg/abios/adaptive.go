@@ -0,0 +1,193 @@
+package abios
+
+import (
+	"expvar"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// adaptiveMetrics counts how often upstream "throttled" adaptive clients and
+// how often it "paused" them. Client.RateLimit tells the rate of each.
+var adaptiveMetrics = expvar.NewMap("abios_rate_limit")
+
+// AdaptiveRateLimit lets the client's rate limit follow what Abios says
+// (AIMD): every successful response raises the rate by Increase, every 429
+// multiplies it by Decrease, and quota headers cap it to what is left of the
+// quota. A 429's Retry-After, or a quota used up, pauses all outbound
+// traffic until it passes, retries included.
+type AdaptiveRateLimit struct {
+	// MinRequestsPerSec is the floor, a tenth of a request a second when
+	// zero.
+	MinRequestsPerSec float64
+	// MaxRequestsPerSec is the ceiling, the client's rate limit when zero.
+	MaxRequestsPerSec float64
+	// Increase is added per successful response, a twentieth of the
+	// ceiling when zero.
+	Increase float64
+	// Decrease multiplies the rate per 429, a half when zero.
+	Decrease float64
+}
+
+// WithAdaptiveRateLimit adjusts the rate limit of WithRateLimit from
+// upstream responses, starting at it. The burst keeps its ratio to the
+// rate.
+func WithAdaptiveRateLimit(a AdaptiveRateLimit) Option {
+	return func(c *config) {
+		c.adaptive = &a
+	}
+}
+
+// adaptiveTransport sits below the retry transport, so it sees every
+// response and holds back retries during a pause as well.
+type adaptiveTransport struct {
+	limiter    *rate.Limiter
+	policy     AdaptiveRateLimit
+	burstRatio float64
+	transport  http.RoundTripper
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+func newAdaptiveTransport(limiter *rate.Limiter, policy AdaptiveRateLimit, transport http.RoundTripper) *adaptiveTransport {
+	current := float64(limiter.Limit())
+	if policy.MaxRequestsPerSec <= 0 {
+		policy.MaxRequestsPerSec = current
+	}
+	if policy.MinRequestsPerSec <= 0 {
+		policy.MinRequestsPerSec = min(0.1, policy.MaxRequestsPerSec)
+	}
+	if policy.Increase <= 0 {
+		policy.Increase = policy.MaxRequestsPerSec / 20
+	}
+	if policy.Decrease <= 0 || policy.Decrease >= 1 {
+		policy.Decrease = 0.5
+	}
+
+	t := &adaptiveTransport{
+		limiter:    limiter,
+		policy:     policy,
+		burstRatio: 1,
+		transport:  transport,
+	}
+	if current > 0 {
+		t.burstRatio = float64(limiter.Burst()) / current
+	}
+	t.setRate(time.Now(), current)
+	return t
+}
+
+func (t *adaptiveTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if wait := t.pause(); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	t.observe(resp)
+	return resp, nil
+}
+
+func (t *adaptiveTransport) pause() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return time.Until(t.pausedUntil)
+}
+
+// observe moves the rate by one response.
+func (t *adaptiveTransport) observe(resp *http.Response) {
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	limit := float64(t.limiter.Limit())
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		limit *= t.policy.Decrease
+		adaptiveMetrics.Add("throttled", 1)
+
+		wait, ok := parseRetryAfter(resp.Header.Get(retryAfterHeaderKey))
+		if !ok {
+			wait = time.Second
+		}
+		t.pauseUntil(now.Add(wait))
+	case resp.StatusCode < http.StatusBadRequest:
+		limit += t.policy.Increase
+	}
+
+	if remaining, reset, ok := parseQuota(resp.Header, now); ok {
+		if remaining == 0 {
+			t.pauseUntil(now.Add(reset))
+		} else if reset > 0 {
+			limit = min(limit, float64(remaining)/reset.Seconds())
+		}
+	}
+
+	t.setRate(now, limit)
+}
+
+func (t *adaptiveTransport) pauseUntil(until time.Time) {
+	if until.After(t.pausedUntil) {
+		t.pausedUntil = until
+		adaptiveMetrics.Add("paused", 1)
+	}
+}
+
+// setCeiling replaces MaxRequestsPerSec and the burst ratio, as if the
+// transport had been built with a limit of rps and burst.
+func (t *adaptiveTransport) setCeiling(rps float64, burst int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.policy.MaxRequestsPerSec = rps
+	t.policy.MinRequestsPerSec = min(t.policy.MinRequestsPerSec, rps)
+	if rps > 0 {
+		t.burstRatio = float64(burst) / rps
+	}
+	t.setRate(time.Now(), float64(t.limiter.Limit()))
+}
+
+func (t *adaptiveTransport) setRate(now time.Time, limit float64) {
+	limit = min(max(limit, t.policy.MinRequestsPerSec), t.policy.MaxRequestsPerSec)
+
+	t.limiter.SetLimitAt(now, rate.Limit(limit))
+	t.limiter.SetBurstAt(now, max(int(limit*t.burstRatio), 1))
+}
+
+// parseQuota reads the quota headers, X-RateLimit-Remaining and
+// X-RateLimit-Reset or their unprefixed RateLimit- forms. Reset is in
+// seconds from now, or a Unix time.
+func parseQuota(h http.Header, now time.Time) (remaining int, reset time.Duration, ok bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		remaining, err := strconv.Atoi(h.Get(prefix + "Remaining"))
+		if err != nil || remaining < 0 {
+			continue
+		}
+
+		secs, err := strconv.ParseInt(h.Get(prefix+"Reset"), 10, 64)
+		switch {
+		case err != nil || secs < 0:
+			secs = 0
+		case secs > 1e9:
+			// a Unix time rather than a delay
+			return remaining, max(time.Unix(secs, 0).Sub(now), 0), true
+		}
+		return remaining, time.Duration(secs) * time.Second, true
+	}
+	return 0, 0, false
+}
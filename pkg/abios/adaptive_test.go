@@ -0,0 +1,162 @@
+package abios_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func currentRate(c *abios.Client) float64 {
+	rps, _ := c.RateLimit()
+	return rps
+}
+
+func TestAdaptiveRateAIMD(t *testing.T) {
+	_, baseURL := newRecordingServer(t,
+		respond(http.StatusTooManyRequests, "Retry-After", "0"),
+		respond(http.StatusTooManyRequests, "Retry-After", "0"),
+		respond(http.StatusOK),
+		respond(http.StatusOK, "X-RateLimit-Remaining", "2", "X-RateLimit-Reset", "10"),
+	)
+	client := abios.NewClient(
+		abios.WithBaseURL(baseURL),
+		abios.WithRateLimit(10, 10),
+		abios.WithAdaptiveRateLimit(abios.AdaptiveRateLimit{}),
+	)
+	assert.Equal(t, 10.0, currentRate(client))
+
+	// two 429s halve the rate twice before the retry succeeds
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+	assert.InDelta(t, 2.5+0.5, currentRate(client), 0.001)
+
+	// the quota allows 2 requests in the next 10 seconds
+	_, err = client.GetGames(context.Background())
+	require.NoError(t, err)
+	assert.InDelta(t, 0.2, currentRate(client), 0.001)
+}
+
+// Each client reports its own rate, however many there are.
+func TestAdaptiveRatePerClient(t *testing.T) {
+	_, throttledURL := newRecordingServer(t, respond(http.StatusTooManyRequests, "Retry-After", "0"))
+	_, otherURL := newRecordingServer(t)
+	throttled := abios.NewClient(
+		abios.WithBaseURL(throttledURL),
+		abios.WithRateLimit(10, 10),
+		abios.WithAdaptiveRateLimit(abios.AdaptiveRateLimit{Increase: 1}),
+	)
+	other := abios.NewClient(
+		abios.WithBaseURL(otherURL),
+		abios.WithRateLimit(8, 8),
+		abios.WithAdaptiveRateLimit(abios.AdaptiveRateLimit{}),
+	)
+
+	_, err := throttled.GetGames(context.Background())
+	require.NoError(t, err)
+
+	rps, burst := throttled.RateLimit()
+	assert.Equal(t, 5.0+1, rps)
+	assert.Equal(t, 6, burst)
+	rps, burst = other.RateLimit()
+	assert.Equal(t, 8.0, rps)
+	assert.Equal(t, 8, burst)
+}
+
+func TestAdaptiveRateFloorAndCeiling(t *testing.T) {
+	_, baseURL := newRecordingServer(t,
+		respond(http.StatusOK, "RateLimit-Remaining", "1", "RateLimit-Reset", "1000"),
+	)
+	client := abios.NewClient(
+		abios.WithBaseURL(baseURL),
+		abios.WithRateLimit(100, 100),
+		abios.WithAdaptiveRateLimit(abios.AdaptiveRateLimit{MinRequestsPerSec: 1, MaxRequestsPerSec: 4, Increase: 1}),
+	)
+	assert.Equal(t, 4.0, currentRate(client))
+
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, currentRate(client))
+
+	for range 5 {
+		_, err = client.GetGames(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 4.0, currentRate(client))
+}
+
+// A 429 holds back every request, not just the one retrying.
+func TestAdaptivePauseIsGlobal(t *testing.T) {
+	_, baseURL := newRecordingServer(t, respond(http.StatusTooManyRequests, "Retry-After", "1"))
+	client := abios.NewClient(
+		abios.WithBaseURL(baseURL),
+		abios.WithRateLimit(100, 100),
+		abios.WithRetryPolicy(abios.RetryPolicy{MaxAttempts: 1}),
+		abios.WithAdaptiveRateLimit(abios.AdaptiveRateLimit{}),
+	)
+
+	_, err := client.GetGames(context.Background())
+	require.Error(t, err)
+
+	start := time.Now()
+	_, err = client.GetTeamsByID(context.Background(), []int{1})
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+}
+
+func TestAdaptivePauseOnQuotaUsedUp(t *testing.T) {
+	_, baseURL := newRecordingServer(t, respond(http.StatusOK, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", "1"))
+	client := abios.NewClient(
+		abios.WithBaseURL(baseURL),
+		abios.WithRateLimit(100, 100),
+		abios.WithAdaptiveRateLimit(abios.AdaptiveRateLimit{}),
+	)
+
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.GetGames(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+}
+
+func TestAdaptivePauseCancelled(t *testing.T) {
+	_, baseURL := newRecordingServer(t, respond(http.StatusOK, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", "60"))
+	client := abios.NewClient(
+		abios.WithBaseURL(baseURL),
+		abios.WithRateLimit(100, 100),
+		abios.WithAdaptiveRateLimit(abios.AdaptiveRateLimit{}),
+	)
+
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.GetGames(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestAdaptiveSetRateLimit(t *testing.T) {
+	_, baseURL := newRecordingServer(t)
+	client := abios.NewClient(
+		abios.WithBaseURL(baseURL),
+		abios.WithRateLimit(10, 10),
+		abios.WithAdaptiveRateLimit(abios.AdaptiveRateLimit{}),
+	)
+
+	// a lower ceiling brings the rate down with it
+	client.SetRateLimit(4, 4)
+	assert.Equal(t, 4.0, currentRate(client))
+
+	for range 3 {
+		_, err := client.GetGames(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 4.0, currentRate(client))
+}
@@ -0,0 +1,231 @@
+package abios
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// CassetteMode selects how a CassetteTransport treats the network.
+type CassetteMode string
+
+const (
+	// CassetteRecord sends every request upstream and records the exchange.
+	CassetteRecord CassetteMode = "record"
+	// CassetteReplay serves every request from the cassette and fails on
+	// requests that were never recorded, without touching the network.
+	CassetteReplay CassetteMode = "replay"
+	// CassetteReplayOrRecord replays recorded requests and records the rest.
+	CassetteReplayOrRecord CassetteMode = "replay-or-record"
+)
+
+// ErrNotRecorded is returned in replay mode for requests missing from the
+// cassette.
+var ErrNotRecorded = errors.New("abios: request not recorded in cassette")
+
+const redacted = "REDACTED"
+
+// redactedHeaders never reach a cassette file.
+var redactedHeaders = []string{abiosAuthHeaderKey, "Authorization"}
+
+// CassetteTransport records Abios request/response pairs to a cassette
+// directory, one JSON file per request, and replays them. Requests are
+// matched on method and URL, so replay is deterministic however often or
+// in whatever order they are made. Sit it at the bottom of the client's
+// transport chain, below auth, rate limiting and retries:
+//
+//	transport, err := abios.NewCassetteTransport("testdata/cassettes", abios.CassetteReplay, nil)
+//	client := abios.NewClient(abios.WithToken(token), abios.WithTransport(transport))
+type CassetteTransport struct {
+	dir  string
+	mode CassetteMode
+	next http.RoundTripper
+
+	mu sync.Mutex
+}
+
+// interaction is the file format of one recorded exchange.
+type interaction struct {
+	Request struct {
+		Method string      `json:"method"`
+		URL    string      `json:"url"`
+		Header http.Header `json:"header,omitempty"`
+	} `json:"request"`
+	Response struct {
+		Status int         `json:"status"`
+		Header http.Header `json:"header,omitempty"`
+		// Body holds JSON bodies as is, for readable cassettes, and
+		// BodyText everything else.
+		Body     json.RawMessage `json:"body,omitempty"`
+		BodyText string          `json:"body_text,omitempty"`
+	} `json:"response"`
+}
+
+// NewCassetteTransport returns a transport over the cassette in dir. next
+// carries the requests that are recorded and defaults to
+// http.DefaultTransport.
+func NewCassetteTransport(dir string, mode CassetteMode, next http.RoundTripper) (*CassetteTransport, error) {
+	switch mode {
+	case CassetteRecord, CassetteReplay, CassetteReplayOrRecord:
+	default:
+		return nil, fmt.Errorf("abios: unknown cassette mode %q", mode)
+	}
+
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	if mode != CassetteReplay {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &CassetteTransport{dir: dir, mode: mode, next: next}, nil
+}
+
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := t.path(req)
+
+	if t.mode != CassetteRecord {
+		resp, err := t.replay(req, path)
+		if err == nil {
+			return resp, nil
+		}
+		if t.mode == CassetteReplay || !errors.Is(err, ErrNotRecorded) {
+			return nil, err
+		}
+	}
+
+	return t.record(req, path)
+}
+
+func (t *CassetteTransport) replay(req *http.Request, path string) (*http.Response, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNotRecorded, req.Method, req.URL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rec interaction
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("abios: corrupt cassette %s: %w", path, err)
+	}
+
+	body := []byte(rec.Response.BodyText)
+	if len(rec.Response.Body) > 0 {
+		body = rec.Response.Body
+	}
+
+	header := rec.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rec.Response.Status, http.StatusText(rec.Response.Status)),
+		StatusCode:    rec.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *CassetteTransport) record(req *http.Request, path string) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var rec interaction
+	rec.Request.Method = req.Method
+	rec.Request.URL = req.URL.String()
+	rec.Request.Header = redact(req.Header)
+	rec.Response.Status = resp.StatusCode
+	rec.Response.Header = redact(resp.Header)
+	rec.Response.Header.Del("Content-Length")
+	if json.Valid(body) {
+		rec.Response.Body = body
+	} else {
+		rec.Response.BodyText = string(body)
+	}
+
+	b, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// written to a temporary file first so a concurrent replay never sees
+	// half a cassette
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(b, '\n'), 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// path names the cassette file of a request after its method and URL path,
+// with a hash of the full URL to tell filters apart.
+func (t *CassetteTransport) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + canonicalURL(req)))
+
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(req.URL.Path, "_"), "_")
+	if name == "" {
+		name = "root"
+	}
+
+	return filepath.Join(t.dir, fmt.Sprintf("%s_%s_%s.json", strings.ToLower(req.Method), name, hex.EncodeToString(sum[:6])))
+}
+
+// canonicalURL ignores the host and the order of query parameters, so
+// cassettes replay against any base URL.
+func canonicalURL(req *http.Request) string {
+	return req.URL.Path + "?" + req.URL.Query().Encode()
+}
+
+func redact(h http.Header) http.Header {
+	h = h.Clone()
+	if h == nil {
+		return nil
+	}
+
+	for _, name := range redactedHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+	return h
+}
@@ -0,0 +1,115 @@
+package abios_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/benjaminmishra/abios-apis/internal/models"
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countingTeamsServer(t *testing.T, hits *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		assert.Equal(t, "secret-token", r.Header.Get("Abios-Secret"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 100, "name": "Team A"}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func cassetteClient(t *testing.T, baseURL, dir string, mode abios.CassetteMode) abios.AbiosClient {
+	t.Helper()
+
+	cassette, err := abios.NewCassetteTransport(dir, mode, nil)
+	require.NoError(t, err)
+
+	return abios.NewClient(abios.WithBaseURL(baseURL), abios.WithToken("secret-token"), abios.WithRateLimit(100, 100), abios.WithTransport(cassette))
+}
+
+func TestCassetteRecordThenReplay(t *testing.T) {
+	var hits atomic.Int32
+	srv := countingTeamsServer(t, &hits)
+	dir := t.TempDir()
+
+	recorder := cassetteClient(t, srv.URL, dir, abios.CassetteRecord)
+	teams, err := recorder.GetTeamsByID(context.Background(), []int{100})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Team{{ID: 100, Name: "Team A"}}, teams)
+	assert.Equal(t, int32(1), hits.Load())
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Contains(t, filepath.Base(files[0]), "get_teams_")
+
+	cassette, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(cassette), "secret-token")
+	assert.Contains(t, string(cassette), `"REDACTED"`)
+	assert.Contains(t, string(cassette), `"name": "Team A"`)
+
+	// replay needs neither the network nor the original host
+	srv.Close()
+	replayer := cassetteClient(t, "http://abios.invalid", dir, abios.CassetteReplay)
+	for range 2 {
+		teams, err = replayer.GetTeamsByID(context.Background(), []int{100})
+		require.NoError(t, err)
+		assert.Equal(t, []models.Team{{ID: 100, Name: "Team A"}}, teams)
+	}
+	assert.Equal(t, int32(1), hits.Load())
+}
+
+func TestCassetteReplayMiss(t *testing.T) {
+	client := cassetteClient(t, "http://abios.invalid", t.TempDir(), abios.CassetteReplay)
+
+	_, err := client.GetTeamsByID(context.Background(), []int{100})
+	require.ErrorIs(t, err, abios.ErrNotRecorded)
+}
+
+func TestCassetteReplayOrRecord(t *testing.T) {
+	var hits atomic.Int32
+	srv := countingTeamsServer(t, &hits)
+	client := cassetteClient(t, srv.URL, t.TempDir(), abios.CassetteReplayOrRecord)
+
+	for range 3 {
+		_, err := client.GetTeamsByID(context.Background(), []int{100})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), hits.Load())
+
+	// a different filter is a different request
+	_, err := client.GetTeamsByID(context.Background(), []int{200})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), hits.Load())
+}
+
+func TestCassetteRecordsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream broke", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	dir := t.TempDir()
+
+	_, err := cassetteClient(t, srv.URL, dir, abios.CassetteRecord).GetGames(context.Background())
+	require.EqualError(t, err, "abios: unexpected status 500")
+
+	_, err = cassetteClient(t, srv.URL, dir, abios.CassetteReplay).GetGames(context.Background())
+	require.EqualError(t, err, "abios: unexpected status 500")
+}
+
+func TestCassetteUnknownMode(t *testing.T) {
+	_, err := abios.NewCassetteTransport(t.TempDir(), "rewind", nil)
+	require.EqualError(t, err, `abios: unknown cassette mode "rewind"`)
+}
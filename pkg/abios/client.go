@@ -0,0 +1,344 @@
+package abios
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultBaseURL is the Atlas v3 API.
+const DefaultBaseURL = "https://atlas.abiosgaming.com/v3"
+
+// AbiosClient is the part of Client the live data needs, for callers that
+// want to substitute it in tests.
+type AbiosClient interface {
+	GetLiveSeries(ctx context.Context, gameIDs []int) ([]Series, error)
+	GetGames(ctx context.Context) ([]Game, error)
+	GetRostersByID(ctx context.Context, rosterIDs []int) ([]Roster, error)
+	GetTeamsByID(ctx context.Context, teamIDs []int) ([]Team, error)
+	GetPlayersByID(ctx context.Context, playerIDs []int) ([]Player, error)
+}
+
+var _ AbiosClient = (*Client)(nil)
+
+// Client calls the Atlas API. Every request carries the Abios secret, waits
+// for the client's rate limit in the order of its Priority and is retried on
+// 429 as the RetryPolicy says. A Client is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	drift      *DriftDetector
+	strict     bool
+	logger     *slog.Logger
+
+	limiter     *rate.Limiter
+	adaptive    *adaptiveTransport
+	credentials *credentials
+	// timeout is the time.Duration of WithTimeout
+	timeout atomic.Int64
+}
+
+// Option configures a Client.
+type Option func(c *config)
+
+type config struct {
+	baseURL       string
+	tokens        TokenSource
+	pool          *TokenPool
+	adaptive      *AdaptiveRateLimit
+	timeout       time.Duration
+	bulkWaitLimit time.Duration
+	rps           float64
+	burst         int
+	retry         RetryPolicy
+	transport     http.RoundTripper
+	drift         *DriftDetector
+	strict        bool
+	logger        *slog.Logger
+}
+
+// WithBaseURL points the client at another Atlas deployment, or at a stand-in.
+func WithBaseURL(baseURL string) Option {
+	return func(c *config) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithToken authenticates with a fixed Abios secret.
+func WithToken(token string) Option {
+	return WithTokenSource(StaticToken(token))
+}
+
+// WithTokenSource asks ts for the Abios secret on every request.
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *config) {
+		c.tokens = ts
+	}
+}
+
+// WithTimeout bounds every call, retries and rate limit waits included.
+// Zero means no timeout; the default is 10 seconds.
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.timeout = d
+	}
+}
+
+// WithRateLimit caps outgoing requests at rps a second with bursts of
+// burst. The default is 5 a second with bursts of 10.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *config) {
+		c.rps = rps
+		c.burst = burst
+	}
+}
+
+// WithRetryPolicy replaces DefaultRetryPolicy.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *config) {
+		c.retry = p
+	}
+}
+
+// WithDriftDetector records the schema drift of every response in d.
+func WithDriftDetector(d *DriftDetector) Option {
+	return func(c *config) {
+		c.drift = d
+	}
+}
+
+// WithStrictDecoding fails requests whose response lacks fields the models
+// need or sends them with another type, with ErrSchemaDrift. Fields the
+// models do not know never fail a request.
+func WithStrictDecoding() Option {
+	return func(c *config) {
+		c.strict = true
+	}
+}
+
+// WithLogger logs what the client notices, such as schema drift and
+// throttled requests, to logger. By default the client logs nothing.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// WithTransport replaces http.DefaultTransport at the bottom of the
+// client's transport chain, e.g. with a CassetteTransport. Auth, rate
+// limiting and retries still wrap it.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *config) {
+		c.transport = rt
+	}
+}
+
+// NewClient returns a client of DefaultBaseURL, changed by opts. Without
+// WithToken or WithTokenSource requests go out unauthenticated.
+func NewClient(opts ...Option) *Client {
+	cfg := config{
+		baseURL:   DefaultBaseURL,
+		tokens:    StaticToken(""),
+		timeout:   10 * time.Second,
+		rps:       5,
+		burst:     10,
+		retry:     DefaultRetryPolicy,
+		transport: http.DefaultTransport,
+		logger:    slog.New(slog.DiscardHandler),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	creds := &credentials{tokens: cfg.tokens, pool: cfg.pool}
+
+	// pooled tokens authenticate below the retries, so a throttled token
+	// fails over to the next before the client backs off
+	var base http.RoundTripper = &poolTransport{transport: cfg.transport}
+
+	limiter := rate.NewLimiter(rate.Limit(cfg.rps), cfg.burst)
+	var adaptive *adaptiveTransport
+	if cfg.adaptive != nil {
+		adaptive = newAdaptiveTransport(limiter, *cfg.adaptive, base)
+		base = adaptive
+	}
+
+	var transport http.RoundTripper = &rateLimitTransport{
+		scheduler: newScheduler(limiter, cfg.bulkWaitLimit),
+		transport: &retryTransport{
+			transport: base,
+			policy:    cfg.retry,
+			logger:    cfg.logger,
+		},
+	}
+	transport = &authTransport{credentials: creds, transport: transport}
+
+	c := &Client{
+		baseURL:     cfg.baseURL,
+		httpClient:  &http.Client{Transport: transport},
+		drift:       cfg.drift,
+		strict:      cfg.strict,
+		logger:      cfg.logger,
+		limiter:     limiter,
+		adaptive:    adaptive,
+		credentials: creds,
+	}
+	c.timeout.Store(int64(cfg.timeout))
+	return c
+}
+
+// SetTimeout changes the timeout of WithTimeout while the client is in use.
+// Calls already under way keep the one they started with.
+func (c *Client) SetTimeout(d time.Duration) {
+	c.timeout.Store(int64(d))
+}
+
+// SetTokenSource authenticates requests with ts from now on, in place of the
+// token source or pool the client had, e.g. when the secret is rotated.
+func (c *Client) SetTokenSource(ts TokenSource) {
+	c.credentials.set(ts, nil)
+}
+
+// SetTokenPool sends requests with the tokens of p from now on, in place of
+// the token source or pool the client had.
+func (c *Client) SetTokenPool(p *TokenPool) {
+	c.credentials.set(nil, p)
+}
+
+// SetRateLimit changes the rate limit of WithRateLimit while the client is
+// in use. With WithAdaptiveRateLimit it moves the ceiling instead, and the
+// rate down to it when above.
+func (c *Client) SetRateLimit(rps float64, burst int) {
+	if c.adaptive != nil {
+		c.adaptive.setCeiling(rps, burst)
+		return
+	}
+
+	now := time.Now()
+	c.limiter.SetLimitAt(now, rate.Limit(rps))
+	c.limiter.SetBurstAt(now, burst)
+}
+
+// RateLimit returns the rate limit requests go out at now. With
+// WithAdaptiveRateLimit that is where upstream has moved it.
+func (c *Client) RateLimit() (rps float64, burst int) {
+	return float64(c.limiter.Limit()), c.limiter.Burst()
+}
+
+// ListSeries returns the series matching q.
+func (c *Client) ListSeries(ctx context.Context, q Query) ([]Series, error) {
+	return getAndDecode[Series](ctx, c, "/series", q)
+}
+
+// ListGames returns the games matching q.
+func (c *Client) ListGames(ctx context.Context, q Query) ([]Game, error) {
+	return getAndDecode[Game](ctx, c, "/games", q)
+}
+
+// ListRosters returns the rosters matching q.
+func (c *Client) ListRosters(ctx context.Context, q Query) ([]Roster, error) {
+	return getAndDecode[Roster](ctx, c, "/rosters", q)
+}
+
+// ListTeams returns the teams matching q.
+func (c *Client) ListTeams(ctx context.Context, q Query) ([]Team, error) {
+	return getAndDecode[Team](ctx, c, "/teams", q)
+}
+
+// ListPlayers returns the players matching q.
+func (c *Client) ListPlayers(ctx context.Context, q Query) ([]Player, error) {
+	return getAndDecode[Player](ctx, c, "/players", q)
+}
+
+// GetLiveSeries returns the live series, optionally restricted to the given
+// game IDs. An empty gameIDs slice means every game.
+func (c *Client) GetLiveSeries(ctx context.Context, gameIDs []int) ([]Series, error) {
+	q := Query{}.Where("lifecycle", Eq, "live")
+	if len(gameIDs) > 0 {
+		q = q.In("game.id", gameIDs...)
+	}
+	return c.ListSeries(ctx, q)
+}
+
+func (c *Client) GetGames(ctx context.Context) ([]Game, error) {
+	return c.ListGames(ctx, Query{})
+}
+
+func (c *Client) GetRostersByID(ctx context.Context, rosterIDs []int) ([]Roster, error) {
+	return c.ListRosters(ctx, Query{}.In("id", rosterIDs...))
+}
+
+func (c *Client) GetTeamsByID(ctx context.Context, teamIDs []int) ([]Team, error) {
+	return c.ListTeams(ctx, Query{}.In("id", teamIDs...))
+}
+
+func (c *Client) GetPlayersByID(ctx context.Context, playerIDs []int) ([]Player, error) {
+	return c.ListPlayers(ctx, Query{}.In("id", playerIDs...))
+}
+
+// getAndDecode fetches a list resource such as "/series". With a drift
+// detector or strict decoding the body is also compared with the model.
+func getAndDecode[T any](ctx context.Context, c *Client, resource string, q Query) ([]T, error) {
+	if timeout := time.Duration(c.timeout.Load()); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	endpoint := c.baseURL + resource
+	if params := q.Values(); len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("abios: unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.drift != nil || c.strict {
+		var items []any
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, err
+		}
+
+		drift := detectDrift(reflect.TypeFor[T](), items)
+		if !drift.empty() {
+			if c.drift != nil {
+				c.drift.record(c.logger, resource, drift)
+			}
+			if c.strict && drift.breaking() {
+				return nil, drift.err(resource)
+			}
+		}
+	}
+
+	var result []T
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
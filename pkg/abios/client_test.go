@@ -0,0 +1,289 @@
+package abios_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    abios.Query
+		expected url.Values
+	}{
+		{name: "Empty", query: abios.Query{}, expected: url.Values{}},
+		{
+			name:     "Conditions",
+			query:    abios.Query{}.Where("lifecycle", abios.Eq, "live").In("game.id", 1, 5),
+			expected: url.Values{"filter": {"lifecycle=live,game.id<={1,5}"}},
+		},
+		{
+			name:     "Not In",
+			query:    abios.Query{}.NotIn("id", 7),
+			expected: url.Values{"filter": {"id!<={7}"}},
+		},
+		{
+			name:     "Paging",
+			query:    abios.Query{}.Where("title", abios.Contains, "final").Skip(50).Take(25),
+			expected: url.Values{"filter": {"title~=final"}, "skip": {"50"}, "take": {"25"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.query.Values())
+		})
+	}
+}
+
+func TestQueriesDoNotShareConditions(t *testing.T) {
+	base := abios.Query{}.Where("a", abios.Eq, "1").Where("b", abios.Eq, "2")
+
+	x := base.Where("c", abios.Eq, "3")
+	y := base.Where("d", abios.Eq, "4")
+
+	assert.Equal(t, "a=1,b=2", base.Filter())
+	assert.Equal(t, "a=1,b=2,c=3", x.Filter())
+	assert.Equal(t, "a=1,b=2,d=4", y.Filter())
+}
+
+// recordingServer answers every request with body and remembers what it
+// was asked.
+type recordingServer struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	// responses answer the first requests in turn, then plain 200s follow
+	responses []response
+	// secretStatuses answer every request made with the secret
+	secretStatuses map[string]int
+}
+
+// response is a status with header key/value pairs.
+type response struct {
+	status int
+	header []string
+}
+
+func respond(status int, header ...string) response {
+	return response{status: status, header: header}
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	resp := respond(http.StatusOK)
+	if len(s.responses) > 0 {
+		resp, s.responses = s.responses[0], s.responses[1:]
+	}
+	if status, ok := s.secretStatuses[r.Header.Get("Abios-Secret")]; ok {
+		resp = respond(status)
+	}
+	s.mu.Unlock()
+
+	for i := 0; i < len(resp.header); i += 2 {
+		w.Header().Set(resp.header[i], resp.header[i+1])
+	}
+	if resp.status == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+		w.Header().Set("Retry-After", "60")
+	}
+	w.WriteHeader(resp.status)
+	_, _ = w.Write([]byte(`[]`))
+}
+
+// collect returns get of every request in the order they came in.
+func (s *recordingServer) collect(get func(r *http.Request) string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make([]string, len(s.requests))
+	for i, r := range s.requests {
+		values[i] = get(r)
+	}
+	return values
+}
+
+func (s *recordingServer) secrets() []string {
+	return s.collect(func(r *http.Request) string { return r.Header.Get("Abios-Secret") })
+}
+
+func (s *recordingServer) filters() []string {
+	return s.collect(func(r *http.Request) string { return r.URL.Query().Get("filter") })
+}
+
+// secretCounts counts the requests made with each secret.
+func (s *recordingServer) secretCounts() map[string]int {
+	counts := map[string]int{}
+	for _, secret := range s.secrets() {
+		counts[secret]++
+	}
+	return counts
+}
+
+func newRecordingServer(t *testing.T, responses ...response) (*recordingServer, string) {
+	t.Helper()
+
+	return serveRecording(t, &recordingServer{responses: responses})
+}
+
+func serveRecording(t *testing.T, rec *recordingServer) (*recordingServer, string) {
+	t.Helper()
+
+	srv := httptest.NewServer(rec)
+	t.Cleanup(srv.Close)
+
+	return rec, srv.URL
+}
+
+func TestListSendsQuery(t *testing.T) {
+	rec, baseURL := newRecordingServer(t)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithToken("token"))
+
+	_, err := client.ListSeries(context.Background(), abios.Query{}.Where("lifecycle", abios.NotEq, "over").Take(10))
+	require.NoError(t, err)
+
+	require.Len(t, rec.requests, 1)
+	assert.Equal(t, "/series", rec.requests[0].URL.Path)
+	assert.Equal(t, url.Values{"filter": {"lifecycle!=over"}, "take": {"10"}}, rec.requests[0].URL.Query())
+}
+
+type rotatingTokens struct {
+	mu     sync.Mutex
+	tokens []string
+}
+
+func (r *rotatingTokens) Token(ctx context.Context) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.tokens) == 0 {
+		return "", errors.New("no token left")
+	}
+	token := r.tokens[0]
+	r.tokens = r.tokens[1:]
+	return token, nil
+}
+
+func TestTokenSource(t *testing.T) {
+	rec, baseURL := newRecordingServer(t)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithTokenSource(&rotatingTokens{tokens: []string{"first", "second"}}))
+	ctx := context.Background()
+
+	_, err := client.GetGames(ctx)
+	require.NoError(t, err)
+	_, err = client.GetGames(ctx)
+	require.NoError(t, err)
+
+	_, err = client.GetGames(ctx)
+	assert.ErrorContains(t, err, "abios: token source: no token left")
+
+	require.Len(t, rec.requests, 2)
+	assert.Equal(t, "first", rec.requests[0].Header.Get("Abios-Secret"))
+	assert.Equal(t, "second", rec.requests[1].Header.Get("Abios-Secret"))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("Retries Within Max Wait", func(t *testing.T) {
+		rec, baseURL := newRecordingServer(t, respond(http.StatusTooManyRequests), respond(http.StatusTooManyRequests))
+		client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithRetryPolicy(abios.RetryPolicy{MaxAttempts: 3, MaxWait: 10 * time.Millisecond}))
+
+		start := time.Now()
+		_, err := client.GetGames(context.Background())
+		require.NoError(t, err)
+
+		// Retry-After asks for a minute
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Len(t, rec.requests, 3)
+	})
+
+	t.Run("Gives Up", func(t *testing.T) {
+		rec, baseURL := newRecordingServer(t, respond(http.StatusTooManyRequests), respond(http.StatusTooManyRequests))
+		client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithRetryPolicy(abios.RetryPolicy{MaxAttempts: 2, MaxWait: time.Millisecond}))
+
+		_, err := client.GetGames(context.Background())
+		assert.ErrorContains(t, err, "abios: too many retries after 2 attempts")
+		assert.Len(t, rec.requests, 2)
+	})
+
+	t.Run("Stops When Cancelled", func(t *testing.T) {
+		_, baseURL := newRecordingServer(t, respond(http.StatusTooManyRequests))
+		client := abios.NewClient(abios.WithBaseURL(baseURL))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.GetGames(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestSetRateLimit(t *testing.T) {
+	_, baseURL := newRecordingServer(t)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithRateLimit(0.1, 1))
+
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	// the old limit would hold the next request back ten seconds
+	client.SetRateLimit(100, 1)
+
+	start := time.Now()
+	_, err = client.GetGames(context.Background())
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestSetTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithTimeout(time.Second))
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	client.SetTimeout(20 * time.Millisecond)
+	_, err = client.GetGames(context.Background())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestSetTokenSource(t *testing.T) {
+	rec, baseURL := newRecordingServer(t)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithToken("old"))
+
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	client.SetTokenSource(abios.StaticToken("new"))
+	_, err = client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"old", "new"}, rec.secrets())
+}
+
+func TestSetTokenPool(t *testing.T) {
+	rec, baseURL := newRecordingServer(t)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithToken("single"))
+
+	client.SetTokenPool(abios.NewTokenPool(abios.PoolToken{Source: abios.StaticToken("pooled")}))
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	// and back from the pool to a single token
+	client.SetTokenSource(abios.StaticToken("single"))
+	_, err = client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pooled", "single"}, rec.secrets())
+}
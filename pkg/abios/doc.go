@@ -0,0 +1,22 @@
+// Package abios is a Go client for the Abios Atlas esports data API.
+//
+// A Client is configured with options and answers list queries built with
+// Query, decoding into the models of this package:
+//
+//	client := abios.NewClient(abios.WithToken(os.Getenv("ABIOS_TOKEN")))
+//	live, err := client.ListSeries(ctx, abios.Query{}.Where("lifecycle", abios.Eq, "live"))
+//
+// Every request passes through the same chain of transports: the Abios
+// secret from the TokenSource is added, the client's rate limit is waited
+// for, and 429 responses are retried under the RetryPolicy. WithTransport
+// replaces the network at the bottom of the chain, e.g. with a
+// CassetteTransport to record and replay traffic.
+//
+// # Versioning
+//
+// The package follows semantic versioning through the module's release
+// tags. Exported identifiers are not removed or changed incompatibly
+// within a major version; new options, methods and model fields may be
+// added in minor releases. The packages under internal/ carry no such
+// guarantee.
+package abios
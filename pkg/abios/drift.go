@@ -0,0 +1,321 @@
+package abios
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSchemaDrift is returned in strict decoding mode when an Abios response
+// lacks a field the models declare or sends it with another type.
+var ErrSchemaDrift = errors.New("abios: response schema drift")
+
+// driftMetrics counts drifted fields across all detectors, keyed by
+// "<endpoint> <kind> <field>", and is published under /debug/vars.
+var driftMetrics = expvar.NewMap("abios_schema_drift")
+
+// DriftDetector records, per Abios endpoint, the model fields responses lack
+// or send as null (missing) or send with another JSON type (mistyped).
+// Either would otherwise decode silently as zero values, or fail.
+//
+// The models declare only the fields the wrapper uses, a fraction of what
+// Abios sends, so fields beyond them are only reported against a baseline
+// of recorded responses (see LoadBaseline): unexpected fields are those the
+// baseline has never seen. Without a baseline they are not reported.
+type DriftDetector struct {
+	mu        sync.Mutex
+	endpoints map[string]*EndpointDrift
+	// baseline holds the fields of the recorded responses per endpoint
+	baseline map[string]map[string]struct{}
+}
+
+// EndpointDrift is the drift seen on one endpoint. The counts are numbers of
+// responses the field drifted in.
+type EndpointDrift struct {
+	Unexpected map[string]int64 `json:"unexpected,omitempty"`
+	Missing    map[string]int64 `json:"missing,omitempty"`
+	Mistyped   map[string]int64 `json:"mistyped,omitempty"`
+	LastSeen   time.Time        `json:"last_seen"`
+}
+
+func NewDriftDetector() *DriftDetector {
+	return &DriftDetector{endpoints: map[string]*EndpointDrift{}}
+}
+
+// Report returns a copy of the drift recorded so far, keyed by endpoint.
+// Endpoints without drift are left out.
+func (d *DriftDetector) Report() map[string]EndpointDrift {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	report := make(map[string]EndpointDrift, len(d.endpoints))
+	for endpoint, drift := range d.endpoints {
+		report[endpoint] = EndpointDrift{
+			Unexpected: maps.Clone(drift.Unexpected),
+			Missing:    maps.Clone(drift.Missing),
+			Mistyped:   maps.Clone(drift.Mistyped),
+			LastSeen:   drift.LastSeen,
+		}
+	}
+	return report
+}
+
+// LoadBaseline reads the Abios responses recorded in a cassette directory
+// (see CassetteTransport) as the baseline of the fields each endpoint sends.
+// Fields responses carry beyond the models are then reported as unexpected
+// unless the baseline has them.
+func (d *DriftDetector) LoadBaseline(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	baseline := map[string]map[string]struct{}{}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var rec interaction
+		if err := json.Unmarshal(b, &rec); err != nil {
+			return fmt.Errorf("abios: corrupt cassette %s: %w", file, err)
+		}
+
+		var items []any
+		if rec.Response.Status != 200 || json.Unmarshal(rec.Response.Body, &items) != nil {
+			continue
+		}
+		u, err := url.Parse(rec.Request.URL)
+		if err != nil {
+			return fmt.Errorf("abios: corrupt cassette %s: %w", file, err)
+		}
+
+		endpoint := "/" + path.Base(u.Path)
+		if baseline[endpoint] == nil {
+			baseline[endpoint] = map[string]struct{}{}
+		}
+		for _, item := range items {
+			fieldPaths(item, "", baseline[endpoint])
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.baseline = baseline
+	return nil
+}
+
+func (d *DriftDetector) record(logger *slog.Logger, endpoint string, drift *responseDrift) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var unexpected []string
+	if known, ok := d.baseline[endpoint]; ok {
+		for _, field := range drift.unexpected {
+			if _, ok := known[field]; !ok {
+				unexpected = append(unexpected, field)
+			}
+		}
+	}
+	if len(unexpected) == 0 && len(drift.missing) == 0 && len(drift.mistyped) == 0 {
+		return
+	}
+
+	e, ok := d.endpoints[endpoint]
+	if !ok {
+		e = &EndpointDrift{Unexpected: map[string]int64{}, Missing: map[string]int64{}, Mistyped: map[string]int64{}}
+		d.endpoints[endpoint] = e
+	}
+	e.LastSeen = time.Now()
+
+	count := func(kind string, seen map[string]int64, fields []string) {
+		for _, field := range fields {
+			if seen[field] == 0 {
+				logger.Warn("abios: schema drift", "kind", kind, "field", field, "endpoint", endpoint)
+			}
+			seen[field]++
+			driftMetrics.Add(endpoint+" "+kind+" "+field, 1)
+		}
+	}
+	count("unexpected", e.Unexpected, unexpected)
+	count("missing", e.Missing, drift.missing)
+	count("mistyped", e.Mistyped, drift.mistyped)
+}
+
+// responseDrift lists the drifted fields of one response as dotted paths,
+// with [] marking array elements, e.g. "participants[].roster.id".
+type responseDrift struct {
+	unexpected []string
+	missing    []string
+	mistyped   []string
+}
+
+func (r *responseDrift) empty() bool {
+	return len(r.unexpected) == 0 && len(r.missing) == 0 && len(r.mistyped) == 0
+}
+
+// breaking reports whether the response lacks or mistypes a model field.
+// Unexpected fields do not break decoding.
+func (r *responseDrift) breaking() bool {
+	return len(r.missing) > 0 || len(r.mistyped) > 0
+}
+
+func (r *responseDrift) err(endpoint string) error {
+	var parts []string
+	if len(r.missing) > 0 {
+		parts = append(parts, "missing "+strings.Join(r.missing, ", "))
+	}
+	if len(r.mistyped) > 0 {
+		parts = append(parts, "mistyped "+strings.Join(r.mistyped, ", "))
+	}
+	return fmt.Errorf("%w in %s: %s", ErrSchemaDrift, endpoint, strings.Join(parts, "; "))
+}
+
+// detectDrift compares the generically decoded items of a response with
+// the model type they are meant to decode into.
+func detectDrift(t reflect.Type, items []any) *responseDrift {
+	d := &walker{unexpected: map[string]struct{}{}, missing: map[string]struct{}{}, mistyped: map[string]struct{}{}}
+	for _, item := range items {
+		d.walk(t, item, "")
+	}
+
+	return &responseDrift{
+		unexpected: slices.Sorted(maps.Keys(d.unexpected)),
+		missing:    slices.Sorted(maps.Keys(d.missing)),
+		mistyped:   slices.Sorted(maps.Keys(d.mistyped)),
+	}
+}
+
+// walker collects the drifted field paths of a response.
+type walker struct {
+	unexpected, missing, mistyped map[string]struct{}
+}
+
+func (d *walker) walk(t reflect.Type, v any, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// a mistyped item fails decoding of the whole response anyway
+	if path != "" && !jsonKindMatches(t, v) {
+		d.mistyped[path] = struct{}{}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		if items, ok := v.([]any); ok {
+			for _, item := range items {
+				d.walk(t.Elem(), item, path+"[]")
+			}
+		}
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return
+		}
+
+		known := map[string]struct{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, optional := jsonField(f)
+			if name == "" {
+				continue
+			}
+			known[name] = struct{}{}
+
+			value, present := obj[name]
+			if !present || value == nil && f.Type.Kind() != reflect.Pointer {
+				if !optional {
+					d.missing[join(path, name)] = struct{}{}
+				}
+				continue
+			}
+			d.walk(f.Type, value, join(path, name))
+		}
+
+		for name := range obj {
+			if _, ok := known[name]; !ok {
+				d.unexpected[join(path, name)] = struct{}{}
+			}
+		}
+	}
+}
+
+// jsonKindMatches reports whether a generically decoded JSON value decodes
+// into t. null always does; it is missing, not mistyped.
+func jsonKindMatches(t reflect.Type, v any) bool {
+	switch v.(type) {
+	case nil:
+		return true
+	case string:
+		return t.Kind() == reflect.String || t.Kind() == reflect.Interface
+	case float64:
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64, reflect.Interface:
+			return true
+		}
+	case bool:
+		return t.Kind() == reflect.Bool || t.Kind() == reflect.Interface
+	case []any:
+		return t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Interface
+	case map[string]any:
+		return t.Kind() == reflect.Struct || t.Kind() == reflect.Map || t.Kind() == reflect.Interface
+	}
+	return false
+}
+
+// fieldPaths adds the dotted paths of every field of a generically decoded
+// JSON value to paths, named as in responseDrift.
+func fieldPaths(v any, path string, paths map[string]struct{}) {
+	switch v := v.(type) {
+	case []any:
+		for _, item := range v {
+			fieldPaths(item, path+"[]", paths)
+		}
+	case map[string]any:
+		for name, value := range v {
+			paths[join(path, name)] = struct{}{}
+			fieldPaths(value, join(path, name), paths)
+		}
+	}
+}
+
+// jsonField returns the JSON name of a struct field and whether it may be
+// left out, or "" when it is not encoded at all.
+func jsonField(f reflect.StructField) (name string, optional bool) {
+	if !f.IsExported() {
+		return "", false
+	}
+
+	name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		name = f.Name
+	}
+	return name, strings.Contains(opts, "omitempty")
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
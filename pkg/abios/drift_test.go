@@ -0,0 +1,229 @@
+package abios_test
+
+import (
+	"bytes"
+	"context"
+	"expvar"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contractDir is a cassette of real Abios responses, recorded by
+// TestContract with ABIOS_CONTRACT_RECORD=1 and ABIOS_TOKEN set.
+const contractDir = "testdata/contract"
+
+// The recorded Abios responses must decode into the models without missing
+// or mistyped fields. The requests follow the live series to their rosters,
+// teams and players, so every model is covered whatever was live when the
+// cassette was recorded.
+func TestContract(t *testing.T) {
+	// cassettes match on the URL path, so the base URL must be the recorded one
+	baseURL := abios.DefaultBaseURL
+	if u := os.Getenv("ABIOS_API_BASE_URL"); u != "" {
+		baseURL = u
+	}
+
+	mode, token := abios.CassetteReplay, "token"
+	if os.Getenv("ABIOS_CONTRACT_RECORD") != "" {
+		mode, token = abios.CassetteRecord, os.Getenv("ABIOS_TOKEN")
+		require.NotEmpty(t, token, "recording needs ABIOS_TOKEN")
+		require.NoError(t, os.RemoveAll(contractDir))
+	} else if recorded, _ := filepath.Glob(filepath.Join(contractDir, "*.json")); len(recorded) == 0 {
+		const msg = "no recorded Abios responses; record them with ABIOS_CONTRACT_RECORD=1 ABIOS_TOKEN=<token> go test ./pkg/abios -run TestContract"
+		// CI must not pass without checking the contract
+		if os.Getenv("CI") != "" {
+			t.Fatal(msg)
+		}
+		t.Skip(msg)
+	}
+
+	cassette, err := abios.NewCassetteTransport(contractDir, mode, nil)
+	require.NoError(t, err)
+	drift := abios.NewDriftDetector()
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithToken(token), abios.WithTransport(cassette), abios.WithDriftDetector(drift), abios.WithStrictDecoding())
+	ctx := context.Background()
+
+	games, err := client.GetGames(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, games)
+	assert.NotEmpty(t, games[0].Slug)
+
+	series, err := client.GetLiveSeries(ctx, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, series, "record while series are live")
+	assert.NotZero(t, series[0].Game.ID)
+
+	var rosterIDs []int
+	for _, s := range series {
+		for _, p := range s.Participants {
+			rosterIDs = append(rosterIDs, p.Roster.ID)
+		}
+	}
+	rosters, err := client.GetRostersByID(ctx, rosterIDs)
+	require.NoError(t, err)
+	require.NotEmpty(t, rosters)
+
+	var teamIDs, playerIDs []int
+	for _, r := range rosters {
+		teamIDs = append(teamIDs, r.TeamId.ID)
+		for _, p := range r.LineUp.Players {
+			playerIDs = append(playerIDs, p.ID)
+		}
+	}
+	teams, err := client.GetTeamsByID(ctx, teamIDs)
+	require.NoError(t, err)
+	require.NotEmpty(t, teams)
+	assert.NotEmpty(t, teams[0].Name)
+
+	players, err := client.GetPlayersByID(ctx, playerIDs)
+	require.NoError(t, err)
+	require.NotEmpty(t, players)
+	assert.NotEmpty(t, players[0].Nickname)
+
+	assert.Empty(t, drift.Report())
+}
+
+func driftedPlayersServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": 1, "nickname": "ace", "country": {"id": 7}}, {"id": 2, "nick_name": null}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestDriftIsRecorded(t *testing.T) {
+	srv := driftedPlayersServer(t)
+	drift := abios.NewDriftDetector()
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100), abios.WithDriftDetector(drift))
+
+	// without strict decoding the drift only shows as zero values
+	players, err := client.GetPlayersByID(context.Background(), []int{1, 2})
+	require.NoError(t, err)
+	require.Len(t, players, 2)
+	assert.Empty(t, players[0].Nickname)
+
+	_, err = client.GetPlayersByID(context.Background(), []int{1, 2})
+	require.NoError(t, err)
+
+	report := drift.Report()
+	require.Contains(t, report, "/players")
+	assert.Empty(t, report["/players"].Unexpected, "fields beyond the models need a baseline")
+	assert.Equal(t, map[string]int64{"nick_name": 2}, report["/players"].Missing)
+	assert.False(t, report["/players"].LastSeen.IsZero())
+
+	metrics := expvar.Get("abios_schema_drift").(*expvar.Map)
+	assert.NotNil(t, metrics.Get("/players missing nick_name"))
+}
+
+func TestDriftIsLogged(t *testing.T) {
+	srv := driftedPlayersServer(t)
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100),
+		abios.WithDriftDetector(abios.NewDriftDetector()), abios.WithLogger(logger))
+
+	for range 2 {
+		_, err := client.GetPlayersByID(context.Background(), []int{1, 2})
+		require.NoError(t, err)
+	}
+
+	// a field is logged the first time it drifts, not on every response
+	assert.Equal(t, 1, bytes.Count(logs.Bytes(), []byte("\n")), logs.String())
+	assert.Contains(t, logs.String(), "level=WARN")
+	assert.Contains(t, logs.String(), "kind=missing field=nick_name endpoint=/players")
+}
+
+func TestDriftAgainstBaseline(t *testing.T) {
+	// the baseline is recorded from what Abios sent before
+	recorded := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 1, "nick_name": "ace", "country": {"id": 7, "name": "Sweden"}}]`))
+	}))
+	t.Cleanup(recorded.Close)
+
+	dir := t.TempDir()
+	cassette, err := abios.NewCassetteTransport(dir, abios.CassetteRecord, nil)
+	require.NoError(t, err)
+	recorder := abios.NewClient(abios.WithBaseURL(recorded.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100), abios.WithTransport(cassette))
+	_, err = recorder.GetPlayersByID(context.Background(), []int{1})
+	require.NoError(t, err)
+
+	drift := abios.NewDriftDetector()
+	require.NoError(t, drift.LoadBaseline(dir))
+
+	srv := driftedPlayersServer(t)
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100), abios.WithDriftDetector(drift))
+	_, err = client.GetPlayersByID(context.Background(), []int{1, 2})
+	require.NoError(t, err)
+
+	report := drift.Report()
+	assert.Equal(t, map[string]int64{"nickname": 1}, report["/players"].Unexpected, "country is in the baseline")
+	assert.Equal(t, map[string]int64{"nick_name": 1}, report["/players"].Missing)
+
+	metrics := expvar.Get("abios_schema_drift").(*expvar.Map)
+	assert.NotNil(t, metrics.Get("/players unexpected nickname"))
+	assert.Nil(t, metrics.Get("/players unexpected country"))
+}
+
+func TestStrictDecoding(t *testing.T) {
+	srv := driftedPlayersServer(t)
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100), abios.WithStrictDecoding())
+
+	_, err := client.GetPlayersByID(context.Background(), []int{1, 2})
+	require.ErrorIs(t, err, abios.ErrSchemaDrift)
+	assert.EqualError(t, err, "abios: response schema drift in /players: missing nick_name")
+}
+
+// Abios sends far more than the models declare; that never fails a request.
+func TestStrictDecodingIgnoresUnknownFields(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 1, "nick_name": "ace", "first_name": "A", "country": {"id": 7}, "images": []}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100), abios.WithStrictDecoding())
+
+	players, err := client.GetPlayersByID(context.Background(), []int{1})
+	require.NoError(t, err)
+	assert.Equal(t, "ace", players[0].Nickname)
+}
+
+func TestStrictDecodingMistyped(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 1, "nick_name": 7}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	drift := abios.NewDriftDetector()
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100), abios.WithDriftDetector(drift), abios.WithStrictDecoding())
+
+	_, err := client.GetPlayersByID(context.Background(), []int{1})
+	require.ErrorIs(t, err, abios.ErrSchemaDrift)
+	assert.EqualError(t, err, "abios: response schema drift in /players: mistyped nick_name")
+	assert.Equal(t, map[string]int64{"nick_name": 1}, drift.Report()["/players"].Mistyped)
+}
+
+func TestNestedDriftPaths(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id": 1, "title": "Final", "lifecycle": "live", "game": {"id": "5"},
+			"participants": [{"roster": {"id": 11, "seed": 1}}, {"roster": {}}]}]`))
+	}))
+	t.Cleanup(srv.Close)
+
+	client := abios.NewClient(abios.WithBaseURL(srv.URL), abios.WithToken("token"), abios.WithRateLimit(100, 100), abios.WithStrictDecoding())
+
+	_, err := client.GetLiveSeries(context.Background(), nil)
+	require.ErrorIs(t, err, abios.ErrSchemaDrift)
+	assert.ErrorContains(t, err, "missing participants[].roster.id; mistyped game.id")
+}
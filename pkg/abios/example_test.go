@@ -0,0 +1,34 @@
+package abios_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+)
+
+func ExampleNewClient() {
+	client := abios.NewClient(
+		abios.WithToken("secret"),
+		abios.WithTimeout(5*time.Second),
+		abios.WithRateLimit(5, 10),
+		abios.WithRetryPolicy(abios.RetryPolicy{MaxAttempts: 5, DefaultWait: time.Second, MaxWait: 30 * time.Second}),
+	)
+
+	series, err := client.GetLiveSeries(context.Background(), []int{5})
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, s := range series {
+		fmt.Println(s.ID, s.Title)
+	}
+}
+
+func ExampleQuery() {
+	live := abios.Query{}.Where("lifecycle", abios.Eq, "live")
+
+	fmt.Println(live.In("game.id", 1, 5).Take(10).Values().Encode())
+	// Output: filter=lifecycle%3Dlive%2Cgame.id%3C%3D%7B1%2C5%7D&take=10
+}
@@ -0,0 +1,11 @@
+package abios
+
+import "context"
+
+// NewScheduler is the scheduler in front of a client's rate limiter.
+var NewScheduler = newScheduler
+
+// Wait blocks until the request of ctx may go out.
+func (s *scheduler) Wait(ctx context.Context) error {
+	return s.wait(ctx)
+}
@@ -0,0 +1,59 @@
+package abios
+
+// Series is an Atlas series, a match between rosters in one game.
+type Series struct {
+	ID           int           `json:"id"`
+	Title        string        `json:"title"`
+	Lifecycle    string        `json:"lifecycle"`
+	Game         GameId        `json:"game"`
+	Participants []Participant `json:"participants"`
+}
+
+// Participant is one side of a series.
+type Participant struct {
+	Roster RosterId `json:"roster"`
+}
+
+type RosterId struct {
+	ID int `json:"id"`
+}
+
+// Roster is the line-up a team fields in a series.
+type Roster struct {
+	ID     int    `json:"id"`
+	TeamId TeamId `json:"team"`
+	LineUp LineUp `json:"line_up"`
+}
+
+type TeamId struct {
+	ID int `json:"id"`
+}
+
+type PlayerId struct {
+	ID int `json:"id"`
+}
+
+type GameId struct {
+	ID int `json:"id"`
+}
+
+// Game is a title covered by Atlas, e.g. Counter-Strike 2 with slug "cs2".
+type Game struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+type Player struct {
+	ID       int    `json:"id"`
+	Nickname string `json:"nick_name"`
+}
+
+type LineUp struct {
+	Players []PlayerId `json:"players"`
+}
+
+type Team struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,234 @@
+package abios
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// poolMetrics counts requests per pooled token under "<name> requests" and
+// 429 responses under "<name> throttled".
+var poolMetrics = expvar.NewMap("abios_token_pool")
+
+// PoolToken is one credential of a TokenPool with its own quota.
+type PoolToken struct {
+	// Name identifies the token in metrics and errors, never the secret.
+	Name   string
+	Source TokenSource
+	// RequestsPerSec and Burst are the token's quota. Zero RequestsPerSec
+	// means no limit.
+	RequestsPerSec float64
+	Burst          int
+}
+
+// TokenUsage counts what a pooled token was used for.
+type TokenUsage struct {
+	Requests  int64 `json:"requests"`
+	Throttled int64 `json:"throttled"`
+}
+
+// TokenPool spreads requests over several Abios credentials. Each request
+// goes out with the token that has capacity soonest; a 429 rests that token
+// for its Retry-After and the request fails over to the next one, so the
+// client only backs off once every token is exhausted.
+type TokenPool struct {
+	tokens []*pooledToken
+}
+
+type pooledToken struct {
+	PoolToken
+	limiter *rate.Limiter
+
+	mu        sync.Mutex
+	restUntil time.Time
+	usage     TokenUsage
+}
+
+// NewTokenPool returns a pool of tokens, named token-1, token-2 and so on
+// where they have no Name.
+func NewTokenPool(tokens ...PoolToken) *TokenPool {
+	p := &TokenPool{}
+	for i, t := range tokens {
+		if t.Name == "" {
+			t.Name = fmt.Sprintf("token-%d", i+1)
+		}
+		p.tokens = append(p.tokens, &pooledToken{
+			PoolToken: t,
+			limiter:   rate.NewLimiter(tokenLimit(t.RequestsPerSec), max(t.Burst, 1)),
+		})
+	}
+	return p
+}
+
+func tokenLimit(rps float64) rate.Limit {
+	if rps <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(rps)
+}
+
+// SetRateLimit gives every token of the pool a new quota, e.g. when the
+// Abios plan changes. Zero rps means no limit.
+func (p *TokenPool) SetRateLimit(rps float64, burst int) {
+	for _, t := range p.tokens {
+		t.limiter.SetLimit(tokenLimit(rps))
+		t.limiter.SetBurst(max(burst, 1))
+	}
+}
+
+// WithTokenPool sends requests with the tokens of p, in place of
+// WithToken or WithTokenSource. The client-wide rate limit still applies on
+// top of the tokens' own.
+func WithTokenPool(p *TokenPool) Option {
+	return func(c *config) {
+		c.pool = p
+	}
+}
+
+// Usage returns the usage of every token by name.
+func (p *TokenPool) Usage() map[string]TokenUsage {
+	usage := make(map[string]TokenUsage, len(p.tokens))
+	for _, t := range p.tokens {
+		t.mu.Lock()
+		usage[t.Name] = t.usage
+		t.mu.Unlock()
+	}
+	return usage
+}
+
+// poolTransport authenticates each request with a token of the pool the
+// authTransport picked for it. It sits below the retry transport, so
+// failing over happens first. Requests without a pool pass through.
+type poolTransport struct {
+	transport http.RoundTripper
+}
+
+func (t *poolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	pool, _ := req.Context().Value(poolKey{}).(*TokenPool)
+	if pool == nil {
+		return t.transport.RoundTrip(req)
+	}
+
+	tried := make(map[*pooledToken]bool, len(pool.tokens))
+
+	for {
+		token, err := pool.acquire(req.Context(), tried)
+		if err != nil {
+			return nil, err
+		}
+		tried[token] = true
+
+		secret, err := token.Source.Token(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("abios: token source of %s: %w", token.Name, err)
+		}
+
+		outReq := req.Clone(req.Context())
+		outReq.Header.Set(abiosAuthHeaderKey, secret)
+
+		resp, err := t.transport.RoundTrip(outReq)
+		if err != nil {
+			return nil, err
+		}
+
+		throttled := resp.StatusCode == http.StatusTooManyRequests
+		token.count(throttled)
+		if !throttled {
+			return resp, nil
+		}
+
+		wait, ok := parseRetryAfter(resp.Header.Get(retryAfterHeaderKey))
+		if !ok {
+			wait = time.Second
+		}
+		token.rest(wait)
+
+		// the last token's 429 goes up to the retry transport to wait on
+		if len(tried) == len(pool.tokens) {
+			return resp, nil
+		}
+		resp.Body.Close()
+	}
+}
+
+// acquire picks the untried token that can send soonest and waits until it
+// may.
+func (p *TokenPool) acquire(ctx context.Context, tried map[*pooledToken]bool) (*pooledToken, error) {
+	now := time.Now()
+
+	var best *pooledToken
+	var bestRes *rate.Reservation
+	var bestDelay time.Duration
+
+	for _, t := range p.tokens {
+		if tried[t] {
+			continue
+		}
+
+		res := t.limiter.ReserveN(now, 1)
+		if !res.OK() {
+			continue
+		}
+		delay := max(res.DelayFrom(now), t.restingFor(now))
+
+		if best == nil || delay < bestDelay {
+			if bestRes != nil {
+				bestRes.CancelAt(now)
+			}
+			best, bestRes, bestDelay = t, res, delay
+		} else {
+			res.CancelAt(now)
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("abios: token pool has no usable token")
+	}
+	if bestDelay == 0 {
+		return best, nil
+	}
+
+	timer := time.NewTimer(bestDelay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return best, nil
+	case <-ctx.Done():
+		bestRes.Cancel()
+		return nil, ctx.Err()
+	}
+}
+
+func (t *pooledToken) restingFor(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return max(t.restUntil.Sub(now), 0)
+}
+
+func (t *pooledToken) rest(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.restUntil = time.Now().Add(d)
+}
+
+func (t *pooledToken) count(throttled bool) {
+	t.mu.Lock()
+	t.usage.Requests++
+	if throttled {
+		t.usage.Throttled++
+	}
+	t.mu.Unlock()
+
+	poolMetrics.Add(t.Name+" requests", 1)
+	if throttled {
+		poolMetrics.Add(t.Name+" throttled", 1)
+	}
+}
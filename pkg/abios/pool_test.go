@@ -0,0 +1,95 @@
+package abios_test
+
+import (
+	"context"
+	"expvar"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenPoolSpreadsLoad(t *testing.T) {
+	rec, baseURL := newRecordingServer(t)
+	pool := abios.NewTokenPool(
+		abios.PoolToken{Name: "a", Source: abios.StaticToken("secret-a"), RequestsPerSec: 0.001, Burst: 2},
+		abios.PoolToken{Name: "b", Source: abios.StaticToken("secret-b"), RequestsPerSec: 0.001, Burst: 1},
+	)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithTokenPool(pool), abios.WithRateLimit(100, 100))
+
+	for range 3 {
+		_, err := client.GetGames(context.Background())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]int{"secret-a": 2, "secret-b": 1}, rec.secretCounts())
+	assert.Equal(t, map[string]abios.TokenUsage{"a": {Requests: 2}, "b": {Requests: 1}}, pool.Usage())
+
+	// every token is out of capacity for the next thousand seconds
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.GetGames(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTokenPoolFailsOver(t *testing.T) {
+	rec, baseURL := serveRecording(t, &recordingServer{secretStatuses: map[string]int{"secret-a": http.StatusTooManyRequests}})
+	pool := abios.NewTokenPool(
+		abios.PoolToken{Name: "fail-a", Source: abios.StaticToken("secret-a")},
+		abios.PoolToken{Name: "fail-b", Source: abios.StaticToken("secret-b")},
+	)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithTokenPool(pool), abios.WithRateLimit(100, 100))
+
+	start := time.Now()
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	// the throttled token now rests, so the next request skips it
+	_, err = client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), time.Second, "the retry transport must not have slept")
+	assert.Equal(t, map[string]int{"secret-a": 1, "secret-b": 2}, rec.secretCounts())
+	assert.Equal(t, map[string]abios.TokenUsage{"fail-a": {Requests: 1, Throttled: 1}, "fail-b": {Requests: 2}}, pool.Usage())
+
+	metrics := expvar.Get("abios_token_pool").(*expvar.Map)
+	assert.Equal(t, "1", metrics.Get("fail-a throttled").String())
+	assert.Equal(t, "2", metrics.Get("fail-b requests").String())
+}
+
+func TestTokenPoolExhausted(t *testing.T) {
+	rec, baseURL := serveRecording(t, &recordingServer{secretStatuses: map[string]int{
+		"secret-a": http.StatusTooManyRequests,
+		"secret-b": http.StatusTooManyRequests,
+	}})
+	pool := abios.NewTokenPool(
+		abios.PoolToken{Source: abios.StaticToken("secret-a")},
+		abios.PoolToken{Source: abios.StaticToken("secret-b")},
+	)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithTokenPool(pool), abios.WithRetryPolicy(abios.RetryPolicy{MaxAttempts: 1}))
+
+	_, err := client.GetGames(context.Background())
+	assert.ErrorContains(t, err, "abios: too many retries after 1 attempts")
+	assert.Equal(t, map[string]int{"secret-a": 1, "secret-b": 1}, rec.secretCounts())
+	assert.Equal(t, map[string]abios.TokenUsage{"token-1": {Requests: 1, Throttled: 1}, "token-2": {Requests: 1, Throttled: 1}}, pool.Usage())
+}
+
+func TestTokenPoolSetRateLimit(t *testing.T) {
+	rec, baseURL := newRecordingServer(t)
+	pool := abios.NewTokenPool(abios.PoolToken{Name: "slow", Source: abios.StaticToken("secret"), RequestsPerSec: 0.001, Burst: 1})
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithTokenPool(pool), abios.WithRateLimit(100, 100))
+
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	pool.SetRateLimit(100, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = client.GetGames(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"secret": 2}, rec.secretCounts())
+}
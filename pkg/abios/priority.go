@@ -0,0 +1,216 @@
+package abios
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Priority orders requests waiting for the client's rate limit.
+type Priority int
+
+const (
+	// PriorityInteractive is for requests someone is waiting on, and the
+	// default.
+	PriorityInteractive Priority = iota
+	// PriorityBackground is for refreshes nobody waits on directly.
+	PriorityBackground
+	// PriorityBulk is for large jobs that can be shed under load.
+	PriorityBulk
+)
+
+var priorityNames = [...]string{"interactive", "background", "bulk"}
+
+func (p Priority) String() string {
+	if p < 0 || int(p) >= len(priorityNames) {
+		return "unknown"
+	}
+	return priorityNames[p]
+}
+
+// ErrShed is returned for bulk requests the client drops rather than queue
+// past their deadline or the WithBulkWaitLimit.
+var ErrShed = errors.New("abios: bulk request shed under load")
+
+// schedulerMetrics counts requests "<priority> scheduled" and "bulk shed".
+var schedulerMetrics = expvar.NewMap("abios_scheduler")
+
+type priorityKey struct{}
+
+// WithPriority marks the requests made with ctx.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the priority of ctx, interactive when unmarked.
+func PriorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok && p >= PriorityInteractive && p <= PriorityBulk {
+		return p
+	}
+	return PriorityInteractive
+}
+
+// WithBulkWaitLimit sheds bulk requests, with ErrShed, that would wait
+// longer than d for the rate limit. Bulk requests are always shed when the
+// wait would outlast their context's deadline.
+func WithBulkWaitLimit(d time.Duration) Option {
+	return func(c *config) {
+		c.bulkWaitLimit = d
+	}
+}
+
+// scheduler hands out the rate limiter's permits highest priority first,
+// and in arrival order within a priority.
+type scheduler struct {
+	limiter       *rate.Limiter
+	bulkWaitLimit time.Duration
+
+	mu          sync.Mutex
+	queues      [PriorityBulk + 1][]*waiter
+	dispatching bool
+	// spare counts the permits nobody was left waiting for, kept for the
+	// next requests
+	spare int
+}
+
+type waiter struct {
+	ready chan struct{}
+}
+
+func newScheduler(limiter *rate.Limiter, bulkWaitLimit time.Duration) *scheduler {
+	return &scheduler{limiter: limiter, bulkWaitLimit: bulkWaitLimit}
+}
+
+// wait blocks until the request of ctx may go out.
+func (s *scheduler) wait(ctx context.Context) error {
+	p := PriorityFrom(ctx)
+	w := &waiter{ready: make(chan struct{})}
+
+	s.mu.Lock()
+	if s.spare > 0 {
+		s.spare--
+		s.mu.Unlock()
+		schedulerMetrics.Add(p.String()+" scheduled", 1)
+		return nil
+	}
+	if p == PriorityBulk && s.shed(ctx) {
+		s.mu.Unlock()
+		schedulerMetrics.Add("bulk shed", 1)
+		return ErrShed
+	}
+	s.queues[p] = append(s.queues[p], w)
+	if !s.dispatching {
+		s.dispatching = true
+		go s.dispatch()
+	}
+	s.mu.Unlock()
+
+	schedulerMetrics.Add(p.String()+" scheduled", 1)
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		select {
+		case <-w.ready:
+			// the permit came as ctx ended; it goes to the next in line
+			s.release()
+		default:
+			s.queues[p] = slices.DeleteFunc(s.queues[p], func(q *waiter) bool { return q == w })
+		}
+		return ctx.Err()
+	}
+}
+
+// shed tells whether a new bulk request would wait past its deadline or the
+// bulk wait limit. Everything queued goes first.
+func (s *scheduler) shed(ctx context.Context) bool {
+	limit := s.limiter.Limit()
+	if limit == rate.Inf {
+		return false
+	}
+
+	queued := 0
+	for _, q := range s.queues {
+		queued += len(q)
+	}
+
+	missing := float64(queued+1) - s.limiter.Tokens()
+	if missing <= 0 {
+		return false
+	}
+	if limit <= 0 {
+		return true
+	}
+	wait := missing / float64(limit)
+
+	if s.bulkWaitLimit > 0 && wait > s.bulkWaitLimit.Seconds() {
+		return true
+	}
+	deadline, ok := ctx.Deadline()
+	return ok && wait > time.Until(deadline).Seconds()
+}
+
+// dispatch runs while requests are queued, taking one permit at a time and
+// giving it to the highest priority waiter when it becomes available, so
+// late interactive requests overtake queued bulk ones.
+func (s *scheduler) dispatch() {
+	for {
+		s.mu.Lock()
+		if s.empty() {
+			s.dispatching = false
+			s.mu.Unlock()
+			return
+		}
+		s.mu.Unlock()
+
+		res := s.limiter.Reserve()
+		if !res.OK() {
+			// a zero limit never grants; retry when it may have changed
+			time.Sleep(time.Second)
+			continue
+		}
+		time.Sleep(res.Delay())
+
+		s.mu.Lock()
+		s.release()
+		s.mu.Unlock()
+	}
+}
+
+// release gives a permit to the highest priority waiter, or keeps it as a
+// spare when everyone gave up while it was on its way.
+func (s *scheduler) release() {
+	if w := s.pop(); w != nil {
+		close(w.ready)
+		return
+	}
+	s.spare++
+}
+
+func (s *scheduler) empty() bool {
+	for _, q := range s.queues {
+		if len(q) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *scheduler) pop() *waiter {
+	for p, q := range s.queues {
+		if len(q) > 0 {
+			s.queues[p] = q[1:]
+			return q[0]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package abios_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func TestPriorityFrom(t *testing.T) {
+	assert.Equal(t, abios.PriorityInteractive, abios.PriorityFrom(context.Background()))
+
+	ctx := abios.WithPriority(context.Background(), abios.PriorityBulk)
+	assert.Equal(t, abios.PriorityBulk, abios.PriorityFrom(ctx))
+	assert.Equal(t, "bulk", abios.PriorityFrom(ctx).String())
+}
+
+func TestPriorityInteractiveOvertakesBulk(t *testing.T) {
+	rec, baseURL := newRecordingServer(t)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithRateLimit(10, 1))
+
+	bulk := abios.WithPriority(context.Background(), abios.PriorityBulk)
+
+	var wg sync.WaitGroup
+	for i := range 3 {
+		wg.Go(func() {
+			_, err := client.GetTeamsByID(bulk, []int{i})
+			assert.NoError(t, err)
+		})
+	}
+
+	// the first bulk request took the burst, the others are queued
+	time.Sleep(20 * time.Millisecond)
+	_, err := client.GetTeamsByID(context.Background(), []int{99})
+	require.NoError(t, err)
+	wg.Wait()
+
+	got := rec.filters()
+	require.Len(t, got, 4)
+	assert.Equal(t, "id<={99}", got[1])
+}
+
+func TestPriorityBackgroundBeforeBulk(t *testing.T) {
+	rec, baseURL := newRecordingServer(t)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithRateLimit(10, 1))
+
+	// take the burst so everything below queues
+	_, err := client.GetTeamsByID(context.Background(), []int{0})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i, p := range []abios.Priority{abios.PriorityBulk, abios.PriorityBackground, abios.PriorityInteractive} {
+		wg.Go(func() {
+			_, err := client.GetTeamsByID(abios.WithPriority(context.Background(), p), []int{i + 1})
+			assert.NoError(t, err)
+		})
+		time.Sleep(10 * time.Millisecond)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"id<={0}", "id<={3}", "id<={2}", "id<={1}"}, rec.filters())
+}
+
+func TestPriorityShedsBulkPastDeadline(t *testing.T) {
+	_, baseURL := newRecordingServer(t)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithRateLimit(1, 1))
+
+	_, err := client.GetGames(context.Background())
+	require.NoError(t, err)
+
+	// the next permit is a second away
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.GetGames(abios.WithPriority(ctx, abios.PriorityBulk))
+	assert.ErrorIs(t, err, abios.ErrShed)
+	assert.Less(t, time.Since(start), 50*time.Millisecond, "a shed request must not wait")
+
+	// interactive requests wait for the deadline instead
+	_, err = client.GetGames(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPriorityBulkWaitLimit(t *testing.T) {
+	for _, tc := range []struct {
+		limit time.Duration
+		shed  bool
+	}{
+		{limit: 50 * time.Millisecond, shed: true},
+		{limit: 500 * time.Millisecond, shed: false},
+	} {
+		t.Run(strconv.Itoa(int(tc.limit.Milliseconds()))+"ms", func(t *testing.T) {
+			_, baseURL := newRecordingServer(t)
+			client := abios.NewClient(
+				abios.WithBaseURL(baseURL),
+				abios.WithRateLimit(10, 1),
+				abios.WithBulkWaitLimit(tc.limit),
+			)
+
+			bulk := abios.WithPriority(context.Background(), abios.PriorityBulk)
+			_, err := client.GetGames(bulk)
+			require.NoError(t, err)
+
+			// the next permit is about 100ms away
+			_, err = client.GetGames(bulk)
+			if tc.shed {
+				assert.ErrorIs(t, err, abios.ErrShed)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// A request given up on leaves the queue, so the permit goes to the next.
+func TestPriorityCancelledWaiterLeavesQueue(t *testing.T) {
+	rec, baseURL := newRecordingServer(t)
+	client := abios.NewClient(abios.WithBaseURL(baseURL), abios.WithRateLimit(5, 1))
+
+	_, err := client.GetTeamsByID(context.Background(), []int{1})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.GetTeamsByID(ctx, []int{2})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	start := time.Now()
+	_, err = client.GetTeamsByID(abios.WithPriority(context.Background(), abios.PriorityBackground), []int{3})
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), 300*time.Millisecond)
+	assert.Equal(t, []string{"id<={1}", "id<={3}"}, rec.filters())
+}
+
+// A permit granted just as its waiter gives up goes to the next one, or is
+// kept when nobody waits, so cancellations never cost capacity.
+func TestPriorityCancelRaceKeepsPermits(t *testing.T) {
+	// the burst is every permit there is; nothing refills during the test
+	const permits = 20000
+	scheduler := abios.NewScheduler(rate.NewLimiter(rate.Every(time.Hour), permits), 0)
+
+	var granted atomic.Int32
+	for range 20 {
+		var wg sync.WaitGroup
+		for i := range 500 {
+			wg.Go(func() {
+				// give up at all sorts of moments around the grant
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(i%50)*10*time.Microsecond)
+				defer cancel()
+				if scheduler.Wait(ctx) == nil {
+					granted.Add(1)
+				}
+			})
+		}
+		wg.Wait()
+	}
+
+	// every permit not taken above is still there
+	left := 0
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		err := scheduler.Wait(ctx)
+		cancel()
+		if err != nil {
+			break
+		}
+		left++
+	}
+
+	assert.Equal(t, permits, int(granted.Load())+left, "granted %d", granted.Load())
+}
@@ -0,0 +1,100 @@
+package abios
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Op is an Atlas filter operator.
+type Op string
+
+const (
+	Eq        Op = "="
+	NotEq     Op = "!="
+	Less      Op = "<"
+	LessEq    Op = "<="
+	Greater   Op = ">"
+	GreaterEq Op = ">="
+	// Contains matches a case-insensitive substring.
+	Contains Op = "~="
+)
+
+// MaxTake is the largest page Atlas serves.
+const MaxTake = 50
+
+// Query builds the filter and paging parameters of an Atlas list request.
+// The zero value asks for the first page of everything. Methods return a
+// new Query, so a base query can be shared and refined:
+//
+//	live := abios.Query{}.Where("lifecycle", abios.Eq, "live")
+//	series, err := client.ListSeries(ctx, live.In("game.id", 1, 5).Take(10))
+type Query struct {
+	filters []string
+	skip    int
+	take    int
+}
+
+// Where adds the condition "field op value". Fields are dotted paths into
+// the resource, e.g. "game.id".
+func (q Query) Where(field string, op Op, value string) Query {
+	return q.with(field + string(op) + value)
+}
+
+// In adds the condition that field is one of ids. An empty ids matches
+// nothing, as it does upstream.
+func (q Query) In(field string, ids ...int) Query {
+	return q.with(field + "<={" + joinInts(ids) + "}")
+}
+
+// NotIn adds the condition that field is none of ids.
+func (q Query) NotIn(field string, ids ...int) Query {
+	return q.with(field + "!<={" + joinInts(ids) + "}")
+}
+
+// Skip skips the first n results.
+func (q Query) Skip(n int) Query {
+	q.skip = n
+	return q
+}
+
+// Take limits the page to n results, at most MaxTake. Atlas defaults to
+// MaxTake when unset.
+func (q Query) Take(n int) Query {
+	q.take = n
+	return q
+}
+
+// Filter returns the filter parameter, the conditions joined by commas.
+func (q Query) Filter() string {
+	return strings.Join(q.filters, ",")
+}
+
+// Values returns the query as URL parameters.
+func (q Query) Values() url.Values {
+	params := url.Values{}
+	if len(q.filters) > 0 {
+		params.Set("filter", q.Filter())
+	}
+	if q.skip > 0 {
+		params.Set("skip", strconv.Itoa(q.skip))
+	}
+	if q.take > 0 {
+		params.Set("take", strconv.Itoa(q.take))
+	}
+	return params
+}
+
+func (q Query) with(condition string) Query {
+	// copied so queries derived from the same base do not share conditions
+	q.filters = append(q.filters[:len(q.filters):len(q.filters)], condition)
+	return q
+}
+
+func joinInts(ids []int) string {
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = strconv.Itoa(id)
+	}
+	return strings.Join(strIDs, ",")
+}
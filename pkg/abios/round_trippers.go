@@ -0,0 +1,163 @@
+package abios
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	abiosAuthHeaderKey  = "Abios-Secret"
+	retryAfterHeaderKey = "Retry-After"
+)
+
+// RetryPolicy controls how requests answered with 429 Too Many Requests
+// are retried.
+type RetryPolicy struct {
+	// MaxAttempts is how often a request is sent in all, once when below 2.
+	MaxAttempts int
+	// DefaultWait is the pause before a retry when the response has no
+	// usable Retry-After header.
+	DefaultWait time.Duration
+	// MaxWait caps the pause, however long Retry-After asks for. Zero
+	// means no cap.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy is used unless WithRetryPolicy says otherwise.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 3, DefaultWait: time.Second}
+
+// credentials are what a client authenticates with: a token source, or a
+// pool whose tokens authenticate further down the transport chain. Either
+// can be swapped while the client is in use.
+type credentials struct {
+	mu     sync.RWMutex
+	tokens TokenSource
+	pool   *TokenPool
+}
+
+func (c *credentials) get() (TokenSource, *TokenPool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.tokens, c.pool
+}
+
+func (c *credentials) set(tokens TokenSource, pool *TokenPool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.tokens, c.pool = tokens, pool
+}
+
+// poolKey carries the pool a request is authenticated with down to the
+// poolTransport, so a request swapped in between still goes out with the
+// credentials it started with.
+type poolKey struct{}
+
+type authTransport struct {
+	credentials *credentials
+	transport   http.RoundTripper
+}
+
+type rateLimitTransport struct {
+	scheduler *scheduler
+	transport http.RoundTripper
+}
+
+type retryTransport struct {
+	transport http.RoundTripper
+	policy    RetryPolicy
+	logger    *slog.Logger
+}
+
+func (a *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tokens, pool := a.credentials.get()
+	if pool != nil {
+		return a.transport.RoundTrip(req.WithContext(context.WithValue(req.Context(), poolKey{}, pool)))
+	}
+
+	token, err := tokens.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("abios: token source: %w", err)
+	}
+
+	// a RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set(abiosAuthHeaderKey, token)
+
+	return a.transport.RoundTrip(req)
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.scheduler.wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	return t.transport.RoundTrip(req)
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var lastResp *http.Response
+	var err error
+
+	attempts := max(t.policy.MaxAttempts, 1)
+	for attempt := 0; attempt < attempts; attempt++ {
+		// cloning request so body/headers are not consumed
+		newReq := req.Clone(req.Context())
+
+		lastResp, err = t.transport.RoundTrip(newReq)
+		if err != nil {
+			return nil, err
+		}
+
+		if lastResp.StatusCode != http.StatusTooManyRequests || attempt == attempts-1 {
+			break
+		}
+
+		// the retry supersedes this response
+		lastResp.Body.Close()
+
+		wait := t.policy.wait(lastResp.Header.Get(retryAfterHeaderKey))
+		t.logger.Debug("abios: throttled, retrying", "path", req.URL.Path, "attempt", attempt+1, "wait", wait)
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+
+	if lastResp.StatusCode == http.StatusTooManyRequests {
+		lastResp.Body.Close()
+		return nil, fmt.Errorf("abios: too many retries after %d attempts", attempts)
+	}
+
+	return lastResp, nil
+}
+
+// wait returns the pause a Retry-After header asks for, within the policy.
+func (p RetryPolicy) wait(retryAfter string) time.Duration {
+	wait, ok := parseRetryAfter(retryAfter)
+	if !ok {
+		wait = p.DefaultWait
+	}
+	if p.MaxWait > 0 {
+		wait = min(wait, p.MaxWait)
+	}
+	return wait
+}
+
+// parseRetryAfter reads seconds or an HTTP date.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if secs, err := strconv.Atoi(header); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,145 @@
+package abios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource supplies the Abios secret for each request, so it can change
+// while the client runs. Implementations must keep the secret out of their
+// errors, since those end up in logs.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a TokenSource that never changes. It prints as REDACTED.
+type StaticToken string
+
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	return string(t), nil
+}
+
+func (t StaticToken) String() string   { return redacted }
+func (t StaticToken) GoString() string { return redacted }
+
+// EnvToken reads the secret from the environment variable name on every
+// request.
+type EnvToken string
+
+func (name EnvToken) Token(ctx context.Context) (string, error) {
+	token := strings.TrimSpace(os.Getenv(string(name)))
+	if token == "" {
+		return "", fmt.Errorf("abios: environment variable %s is not set", string(name))
+	}
+	return token, nil
+}
+
+// defaultFileCheckInterval is how often a FileTokenSource looks for changes.
+const defaultFileCheckInterval = time.Second
+
+// FileTokenSource reads the secret from a file, such as a mounted
+// Kubernetes secret, and picks up a rotated secret without a restart. The
+// file is checked for changes at most once every CheckInterval.
+type FileTokenSource struct {
+	path string
+
+	// CheckInterval defaults to a second.
+	CheckInterval time.Duration
+
+	mu        sync.Mutex
+	token     string
+	modTime   time.Time
+	size      int64
+	checkedAt time.Time
+}
+
+// NewFileTokenSource reads the secret from path, surrounding whitespace
+// trimmed.
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{path: path, CheckInterval: defaultFileCheckInterval}
+}
+
+func (f *FileTokenSource) Token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if f.token != "" && now.Sub(f.checkedAt) < f.CheckInterval {
+		return f.token, nil
+	}
+
+	// os errors name the file, never its contents
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("abios: token file: %w", err)
+	}
+	f.checkedAt = now
+
+	if f.token != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.token, nil
+	}
+
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("abios: token file: %w", err)
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("abios: token file %s is empty", f.path)
+	}
+
+	f.token, f.modTime, f.size = token, info.ModTime(), info.Size()
+	return f.token, nil
+}
+
+// CommandTokenSource runs a command, such as a secret manager CLI, and uses
+// what it prints as the secret until TTL passes.
+type CommandTokenSource struct {
+	name string
+	args []string
+	ttl  time.Duration
+
+	mu        sync.Mutex
+	token     string
+	fetchedAt time.Time
+}
+
+// NewCommandTokenSource runs name with args for a fresh secret at most
+// once every ttl. A zero ttl runs it for every request.
+func NewCommandTokenSource(ttl time.Duration, name string, args ...string) *CommandTokenSource {
+	return &CommandTokenSource{name: name, args: args, ttl: ttl}
+}
+
+func (c *CommandTokenSource) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && time.Since(c.fetchedAt) < c.ttl {
+		return c.token, nil
+	}
+
+	// only stdout is captured and it stays out of the error, as does stderr
+	// in case the command echoes the secret there
+	out, err := exec.CommandContext(ctx, c.name, c.args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("abios: token command %s failed: %s", c.name, exitErr.ProcessState)
+		}
+		return "", fmt.Errorf("abios: token command %s: %w", c.name, err)
+	}
+
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("abios: token command %s printed nothing", c.name)
+	}
+
+	c.token, c.fetchedAt = token, time.Now()
+	return c.token, nil
+}
@@ -0,0 +1,123 @@
+package abios_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/benjaminmishra/abios-apis/pkg/abios"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticTokenIsRedacted(t *testing.T) {
+	token := abios.StaticToken("s3cret")
+
+	got, err := token.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", got)
+
+	for _, format := range []string{"%v", "%s", "%#v", "%+v"} {
+		assert.NotContains(t, fmt.Sprintf(format, token), "s3cret", format)
+	}
+	assert.NotContains(t, fmt.Sprintf("%+v", struct{ Token abios.StaticToken }{token}), "s3cret")
+}
+
+func TestEnvToken(t *testing.T) {
+	t.Setenv("TEST_ABIOS_TOKEN", " from-env\n")
+
+	got, err := abios.EnvToken("TEST_ABIOS_TOKEN").Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", got)
+
+	_, err = abios.EnvToken("TEST_ABIOS_TOKEN_UNSET").Token(context.Background())
+	assert.EqualError(t, err, "abios: environment variable TEST_ABIOS_TOKEN_UNSET is not set")
+}
+
+func TestFileTokenSourceRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("first\n"), 0o600))
+
+	source := abios.NewFileTokenSource(path)
+	source.CheckInterval = 0
+	ctx := context.Background()
+
+	got, err := source.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "first", got)
+
+	// a rotated secret is mounted by swapping the file
+	rotated := path + ".new"
+	require.NoError(t, os.WriteFile(rotated, []byte("second-token\n"), 0o600))
+	require.NoError(t, os.Rename(rotated, path))
+
+	got, err = source.Token(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "second-token", got)
+
+	require.NoError(t, os.WriteFile(path, []byte("  \n"), 0o600))
+	_, err = source.Token(ctx)
+	assert.EqualError(t, err, "abios: token file "+path+" is empty")
+
+	require.NoError(t, os.Remove(path))
+	_, err = source.Token(ctx)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.NotContains(t, err.Error(), "second-token")
+}
+
+func TestFileTokenSourceCachesBetweenChecks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("first"), 0o600))
+
+	source := abios.NewFileTokenSource(path)
+	source.CheckInterval = time.Hour
+
+	_, err := source.Token(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, []byte("second"), 0o600))
+
+	got, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "first", got)
+}
+
+func TestCommandTokenSource(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	script := `echo run >> "$1"; echo "token-$(wc -l < "$1" | tr -d ' ')"`
+	ctx := context.Background()
+
+	t.Run("Cached For TTL", func(t *testing.T) {
+		source := abios.NewCommandTokenSource(time.Hour, "sh", "-c", script, "sh", counter)
+
+		for range 3 {
+			got, err := source.Token(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, "token-1", got)
+		}
+	})
+
+	t.Run("Refreshed Without TTL", func(t *testing.T) {
+		source := abios.NewCommandTokenSource(0, "sh", "-c", script, "sh", counter)
+
+		first, err := source.Token(ctx)
+		require.NoError(t, err)
+		second, err := source.Token(ctx)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("Failure Hides Output", func(t *testing.T) {
+		source := abios.NewCommandTokenSource(0, "sh", "-c", "echo leaked; echo leaked >&2; exit 3")
+
+		_, err := source.Token(ctx)
+		assert.EqualError(t, err, "abios: token command sh failed: exit status 3")
+	})
+
+	t.Run("Missing Command", func(t *testing.T) {
+		_, err := abios.NewCommandTokenSource(0, "abios-no-such-command").Token(ctx)
+		assert.ErrorContains(t, err, "abios: token command abios-no-such-command")
+	})
+}